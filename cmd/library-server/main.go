@@ -76,12 +76,24 @@ func main() {
 	cfg := config.MustLoad()
 	log := logger.New(cfg.Env)
 
+	duration, bpm, key, explicit := 212, 120.0, "Am", false
+
 	model := dto.Song{
 		Group:       "Muse",
 		Song:        "Supermassive Black Hole",
 		Text:        text,
 		ReleaseDate: "16.07.2006",
 		Patronymic:  "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+		Album: &dto.SongAlbum{
+			Title:       "Black Holes and Revelations",
+			ReleaseDate: "03.07.2006",
+			Type:        "LP",
+			Track:       3,
+		},
+		Duration: &duration,
+		BPM:      &bpm,
+		Key:      &key,
+		Explicit: &explicit,
 	}
 
 	router := chi.NewRouter()
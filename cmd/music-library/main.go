@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
 	"fmt"
 	"log/slog"
 	"music-library/internal/config"
@@ -11,12 +14,14 @@ import (
 	"music-library/internal/logger"
 	"music-library/internal/migrations"
 	libraryservice "music-library/internal/services/library"
+	"music-library/internal/storage/blob"
 	"music-library/internal/storage/library"
 	"music-library/internal/storage/postgresql"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -55,8 +60,34 @@ func main() {
 	}
 	log.Info("migrations applied successfully")
 
+	blobs, err := blob.New(cfg.Attachments)
+	if err != nil {
+		log.Error("failed to create blob store", sl.Err(err))
+		os.Exit(1)
+	}
+
+	if cfg.Search.CursorSecret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Error("failed to generate cursor secret", sl.Err(err))
+			os.Exit(1)
+		}
+		cfg.Search.CursorSecret = hex.EncodeToString(secret)
+		log.Warn("CURSOR_SECRET is not set, cursors will not work after a restart")
+	}
+
 	libraryDB := library.NewLibraryDB(log)
-	libraryService := libraryservice.NewLibraryService(log, pool, libraryDB, cfg.LibraryServer)
+	libraryService := libraryservice.NewLibraryService(log, pool, libraryDB, cfg.LibraryServer, blobs, cfg.Attachments, cfg.Import, cfg.Search)
+
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		code := runImport(context.Background(), libraryService, os.Args[2:])
+		pool.Close()
+		os.Exit(code)
+	}
+
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	go libraryService.RunTrashPurge(purgeCtx, cfg.Trash)
+	log.Info("trash purge started", slog.Duration("retention", cfg.Trash.Retention), slog.Duration("interval", cfg.Trash.PurgeInterval))
 
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
@@ -70,13 +101,13 @@ func main() {
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "X-Next-Cursor", "X-Prev-Cursor"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 	log.Info("cors successfully conected")
 
-	router.Route("/", libraryhandlers.AddHandler(context.TODO(), log, libraryService))
+	router.Route("/", libraryhandlers.AddHandler(context.TODO(), log, libraryService, cfg.Attachments.MaxSize))
 
 	router.Mount("/swagger", httpSwagger.WrapHandler)
 
@@ -103,6 +134,59 @@ func main() {
 	ctx, close := context.WithTimeout(context.Background(), time.Minute)
 	defer close()
 	srv.Shutdown(ctx)
+	stopPurge()
 	pool.Close()
 	log.Info("server was stopped")
 }
+
+// runImport is the import command, it imports a directory of audio files
+// and prints a line per file:
+//
+//	music-library import [-dry-run] <dir>
+func runImport(ctx context.Context, service *libraryservice.LibraryService, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report the files without saving the songs")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: music-library import [-dry-run] <dir>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	report, err := service.ImportDir(ctx, flags.Arg(0), *dryRun, "import")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %s\n", err)
+		return 1
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, file := range report.Files {
+		var song string
+		if file.Group != "" {
+			song = fmt.Sprintf("%s - %s", file.Group, file.Song)
+		}
+		detail := file.Error
+		switch {
+		case file.DuplicateOf != 0:
+			detail = fmt.Sprintf("duplicate of song %d", file.DuplicateOf)
+		case file.DuplicateFile != "":
+			detail = fmt.Sprintf("duplicate of %s", file.DuplicateFile)
+		case file.SongID != 0:
+			detail = fmt.Sprintf("song %d", file.SongID)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", file.Status, file.Path, song, detail)
+	}
+	tw.Flush()
+
+	fmt.Printf("new: %d, duplicates: %d, invalid: %d", report.New, report.Duplicates, report.Invalid)
+	if report.DryRun {
+		fmt.Print(" (dry run, nothing saved)")
+	}
+	fmt.Println()
+	return 0
+}
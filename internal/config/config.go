@@ -18,6 +18,10 @@ type Config struct {
 	Database       `yaml:"database" env-required:"true"`
 	HTTPServer     `yaml:"http_server" env-required:"true"`
 	LibraryServer  `yaml:"library_server" env-required:"true"`
+	Trash          `yaml:"trash"`
+	Attachments    `yaml:"attachments"`
+	Import         `yaml:"import"`
+	Search         `yaml:"search"`
 }
 
 type Database struct {
@@ -43,6 +47,36 @@ type LibraryServer struct {
 	Port     int    `yaml:"port" env-required:"true"`
 }
 
+// Trash configures how long soft deleted songs are kept before the
+// background purge removes them for good.
+type Trash struct {
+	Retention     time.Duration `yaml:"retention" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// Attachments configures the blob store for uploaded files, MaxSize is in
+// bytes and ThumbnailSize is the longest side of an image thumbnail.
+type Attachments struct {
+	Store         string `yaml:"store" env-default:"local"`
+	Dir           string `yaml:"dir" env-default:"./data/attachments"`
+	MaxSize       int64  `yaml:"max_size" env-default:"20971520"`
+	ThumbnailSize int    `yaml:"thumbnail_size" env-default:"256"`
+}
+
+// Import configures the directory the import endpoint may read from, the
+// import command accepts any directory.
+type Import struct {
+	Dir string `yaml:"dir" env-default:"./data/import"`
+}
+
+// Search configures the song search, FuzzyThreshold is the trigram similarity
+// a fuzzy match needs unless the request gives its own. CursorSecret signs the
+// pagination cursors, it comes from the environment like the database password.
+type Search struct {
+	FuzzyThreshold float64 `yaml:"fuzzy_threshold" env-default:"0.3"`
+	CursorSecret   string  `yaml:"cursor_secret" env:"CURSOR_SECRET"`
+}
+
 func MustLoad() *Config {
 	if err := godotenv.Load(".env"); err != nil {
 		fmt.Println(".env file not found")
@@ -73,6 +107,16 @@ func MustLoad() *Config {
 		os.Exit(1)
 	}
 
+	if cfg.Trash.Retention <= 0 || cfg.Trash.PurgeInterval <= 0 {
+		fmt.Printf("trash retention and purge_interval must be positive, got %s and %s", cfg.Trash.Retention, cfg.Trash.PurgeInterval)
+		os.Exit(1)
+	}
+
+	if cfg.Search.FuzzyThreshold <= 0 || cfg.Search.FuzzyThreshold > 1 {
+		fmt.Printf("search fuzzy_threshold must be greater than 0 and not greater than 1, got %g", cfg.Search.FuzzyThreshold)
+		os.Exit(1)
+	}
+
 	cfg.Database.User, cfg.Database.Password, cfg.Database.Name, cfg.Database.Host = dbUser, dbPassword, dbName, dbHost
 	var err error
 	cfg.Database.Port, err = strconv.Atoi(dbPort)
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/partialdate"
+	"music-library/internal/lib/validator"
+	"strings"
+)
+
+type Album struct {
+	Title       string `json:"title" validate:"required" example:"Black Holes and Revelations"`
+	Artist      string `json:"artist" validate:"required" example:"Muse"`
+	ReleaseDate string `json:"release_date" validate:"required" example:"03.07.2006"`
+	Type        string `json:"type" validate:"required,oneof=LP EP single" example:"LP"`
+}
+
+func (a *Album) Validate() error {
+	a.Title = strings.TrimSpace(a.Title)
+	a.Artist = NormalizeName(a.Artist)
+	a.ReleaseDate = strings.TrimSpace(a.ReleaseDate)
+	a.Type = strings.TrimSpace(a.Type)
+
+	if err := validator.Validate(a); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
+
+func (a *Album) ToDBModel() (AlbumDB, error) {
+	releaseDate, err := ParseReleaseDate(a.ReleaseDate, "release_date")
+	if err != nil {
+		return AlbumDB{}, err
+	}
+
+	return AlbumDB{
+		Title:       a.Title,
+		Artist:      a.Artist,
+		ReleaseDate: releaseDate,
+		Type:        a.Type,
+	}, nil
+}
+
+// AlbumDB is an album to save, the release date keeps its precision like the
+// one of a song. An imported album has no Type, its tags do not tell it.
+type AlbumDB struct {
+	Title       string           `json:"title"`
+	Artist      string           `json:"artist"`
+	ReleaseDate partialdate.Date `json:"release_date"`
+	Type        string           `json:"type"`
+	Track       int              `json:"track"`
+}
+
+// SongAlbum is the optional album block of the /info response, without a
+// type the album stays of an unknown type.
+type SongAlbum struct {
+	Title       string `json:"title" validate:"required" example:"Black Holes and Revelations"`
+	ReleaseDate string `json:"releaseDate" example:"03.07.2006"`
+	Type        string `json:"type" validate:"omitempty,oneof=LP EP single" example:"LP"`
+	Track       int    `json:"track" validate:"gte=0" example:"3"`
+}
+
+type AddAlbumTrack struct {
+	SongID   int `json:"song_id" validate:"required,gt=0" example:"1"`
+	Position int `json:"position" validate:"gte=0" example:"3"`
+}
+
+func (t *AddAlbumTrack) Validate() error {
+	if err := validator.Validate(t); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
+
+type ReorderAlbumTracks struct {
+	SongIDs []int `json:"song_ids" validate:"required,min=1,unique,dive,gt=0"`
+}
+
+func (t *ReorderAlbumTracks) Validate() error {
+	if err := validator.Validate(t); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
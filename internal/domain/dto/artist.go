@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+	"strings"
+	"time"
+)
+
+// NormalizeName trims the name and collapses inner whitespace, so "Muse" and
+// " muse " end up as the same artist.
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+type Artist struct {
+	Name       string  `json:"name" validate:"required" example:"Muse"`
+	SortName   *string `json:"sort_name" example:"Muse"`
+	Country    *string `json:"country" validate:"omitempty,iso3166_1_alpha2" example:"GB"`
+	FormedYear *int    `json:"formed_year" validate:"omitempty,gte=1000" example:"1994"`
+}
+
+func (a *Artist) Validate() error {
+	a.Name = NormalizeName(a.Name)
+
+	if a.SortName != nil {
+		val := NormalizeName(*a.SortName)
+		a.SortName = &val
+	}
+
+	if a.Country != nil {
+		val := strings.ToUpper(strings.TrimSpace(*a.Country))
+		a.Country = &val
+	}
+
+	if err := validator.Validate(a); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+
+	if a.FormedYear != nil && *a.FormedYear > time.Now().Year() {
+		return fmt.Errorf("validation error: formed_year can not be in the future")
+	}
+	return nil
+}
+
+type UpdateArtist struct {
+	ID         int `json:"-"`
+	Name       any `json:"name"`
+	SortName   any `json:"sort_name"`
+	Country    any `json:"country"`
+	FormedYear any `json:"formed_year"`
+}
+
+func (u *UpdateArtist) Validate() error {
+	if u.Name == nil && u.SortName == nil && u.Country == nil && u.FormedYear == nil {
+		return fmt.Errorf("validation error: nothing to update")
+	}
+
+	if u.Name != nil {
+		val, ok := u.Name.(string)
+		if !ok {
+			return fmt.Errorf("validation error: name must be a string")
+		}
+		val = NormalizeName(val)
+		if val == "" {
+			return fmt.Errorf("validation error: name can not be empty")
+		}
+		u.Name = val
+	}
+
+	if u.SortName != nil {
+		val, ok := u.SortName.(string)
+		if !ok {
+			return fmt.Errorf("validation error: sort_name must be a string")
+		}
+		u.SortName = NormalizeName(val)
+	}
+
+	if u.Country != nil {
+		val, ok := u.Country.(string)
+		if !ok {
+			return fmt.Errorf("validation error: country must be a string")
+		}
+		country := struct {
+			Country string `json:"country" validate:"iso3166_1_alpha2"`
+		}{Country: strings.ToUpper(strings.TrimSpace(val))}
+		if err := validator.Validate(country); err != "" {
+			return fmt.Errorf("validation error: %s", err)
+		}
+		u.Country = country.Country
+	}
+
+	if u.FormedYear != nil {
+		val, ok := u.FormedYear.(float64)
+		if !ok || val != float64(int(val)) {
+			return fmt.Errorf("validation error: formed_year must be an integer")
+		}
+		if val < 1000 || int(val) > time.Now().Year() {
+			return fmt.Errorf("validation error: formed_year is out of range")
+		}
+		u.FormedYear = int(val)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	KindArtwork    = "artwork"
+	KindSheetMusic = "sheet_music"
+	KindOther      = "other"
+)
+
+// Attachment describes an uploaded file, an empty kind is picked from the
+// content type.
+type Attachment struct {
+	Kind     string `json:"kind" validate:"omitempty,oneof=artwork sheet_music other" example:"artwork"`
+	Filename string `json:"filename" validate:"required,max=255" example:"cover.jpg"`
+}
+
+func (a *Attachment) Validate() error {
+	a.Kind = strings.TrimSpace(a.Kind)
+	// browsers on Windows used to send the full path
+	a.Filename = strings.TrimSpace(filepath.Base(strings.ReplaceAll(a.Filename, "\\", "/")))
+	if a.Filename == "." || a.Filename == "/" {
+		a.Filename = ""
+	}
+
+	if err := validator.Validate(a); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
+
+type AttachmentDB struct {
+	SongID       int
+	Kind         string
+	Filename     string
+	ContentType  string
+	Size         int64
+	SHA256       string
+	BlobKey      string
+	ThumbnailKey *string
+}
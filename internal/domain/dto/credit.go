@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+	"strings"
+)
+
+const (
+	RolePrimary   = "primary"
+	RoleFeaturing = "featuring"
+	RoleProducer  = "producer"
+)
+
+type SongCredit struct {
+	Artist string `json:"artist" validate:"required" example:"Kanye West"`
+	Role   string `json:"role" validate:"required,oneof=featuring producer" example:"featuring"`
+}
+
+func (c *SongCredit) Validate() error {
+	c.Artist = NormalizeName(c.Artist)
+	c.Role = strings.ToLower(strings.TrimSpace(c.Role))
+
+	if err := validator.Validate(c); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
+
+// normalizeArtists normalizes the names and drops empty ones, duplicates and
+// the names of the excluded artist, all compared case-insensitively.
+func normalizeArtists(names []string, exclude string) []string {
+	seen := map[string]bool{strings.ToLower(exclude): true}
+
+	artists := make([]string, 0, len(names))
+	for _, name := range names {
+		name = NormalizeName(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		artists = append(artists, name)
+	}
+	return artists
+}
@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+)
+
+type MergeSong struct {
+	Into int `json:"into" validate:"required,gt=0" example:"1"`
+}
+
+func (m *MergeSong) Validate() error {
+	if err := validator.Validate(m); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
@@ -1,16 +1,69 @@
 package dto
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
-	"time"
+	"music-library/internal/lib/querylang"
+	"strings"
 )
 
+// queryFields are the fields of the q filter.
+var queryFields = querylang.Fields{
+	"group":    querylang.Text,
+	"song":     querylang.Text,
+	"text":     querylang.Text,
+	"album":    querylang.Text,
+	"person":   querylang.Text,
+	"tag":      querylang.Exact,
+	"genre":    querylang.Exact,
+	"key":      querylang.Exact,
+	"isrc":     querylang.Exact,
+	"year":     querylang.Integer,
+	"duration": querylang.Integer,
+	"bpm":      querylang.Number,
+	"date":     querylang.Date,
+}
+
 type Filters struct {
 	Group             any `json:"group"`
 	Song              any `json:"song"`
 	Text              any `json:"text"`
 	ReleaseDateBefore any `json:"release_date_before"`
 	ReleaseDateAfter  any `json:"release_date_after"`
+	Album             any `json:"album"`
+	TagsAny           any `json:"tags_any"`
+	TagsAll           any `json:"tags_all"`
+	Genre             any `json:"genre"`
+	DurationMin       any `json:"duration_min"`
+	DurationMax       any `json:"duration_max"`
+	BPMMin            any `json:"bpm_min"`
+	BPMMax            any `json:"bpm_max"`
+	Key               any `json:"key"`
+	ISRC              any `json:"isrc"`
+	OriginalsOnly     any `json:"originals_only"`
+	CreditedPerson    any `json:"credited_person"`
+	Query             any `json:"query"`
+	QueryLang         any `json:"query_lang"`
+	Fuzzy             any `json:"fuzzy"`
+	FuzzyThreshold    any `json:"fuzzy_threshold"`
+	Q                 any `json:"q" swaggertype:"string" example:"group:muse OR group:radiohead -song:live year:2000..2010"`
+
+	// query is the q filter as it was given, Validate replaces Q with its tree
+	query string
+}
+
+// FiltersKey identifies the listing of validated filters, cursors carry it to
+// be used only with the filters they were made for.
+func FiltersKey(f Filters) string {
+	if f.Q != nil {
+		// the tree does not tell AND from OR in JSON
+		f.Q = f.query
+	}
+	b, _ := json.Marshal(f)
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
 }
 
 func (f *Filters) Validate() error {
@@ -38,14 +91,197 @@ func (f *Filters) Validate() error {
 		f.Text = val
 	}
 
+	if f.Album != nil {
+		val, ok := f.Album.(string)
+		if !ok {
+			return fmt.Errorf("validation error: album filter must be a string")
+		}
+		f.Album = val
+	}
+
+	if f.TagsAny != nil {
+		val, err := labelsFilter(f.TagsAny, "tags_any")
+		if err != nil {
+			return err
+		}
+		f.TagsAny = val
+	}
+
+	if f.TagsAll != nil {
+		val, err := labelsFilter(f.TagsAll, "tags_all")
+		if err != nil {
+			return err
+		}
+		f.TagsAll = val
+	}
+
+	if f.Genre != nil {
+		val, ok := f.Genre.(string)
+		if !ok {
+			return fmt.Errorf("validation error: genre filter must be a string")
+		}
+		f.Genre = strings.ToLower(NormalizeName(val))
+	}
+
+	if f.DurationMin != nil {
+		val, err := intValue(f.DurationMin, "duration_min filter")
+		if err != nil {
+			return err
+		}
+		f.DurationMin = val
+	}
+
+	if f.DurationMax != nil {
+		val, err := intValue(f.DurationMax, "duration_max filter")
+		if err != nil {
+			return err
+		}
+		f.DurationMax = val
+	}
+
+	if f.DurationMin != nil && f.DurationMax != nil && f.DurationMin.(int) > f.DurationMax.(int) {
+		return fmt.Errorf("validation error: duration_min can not be greater than duration_max")
+	}
+
+	if f.BPMMin != nil {
+		val, err := floatValue(f.BPMMin, "bpm_min filter")
+		if err != nil {
+			return err
+		}
+		f.BPMMin = val
+	}
+
+	if f.BPMMax != nil {
+		val, err := floatValue(f.BPMMax, "bpm_max filter")
+		if err != nil {
+			return err
+		}
+		f.BPMMax = val
+	}
+
+	if f.BPMMin != nil && f.BPMMax != nil && f.BPMMin.(float64) > f.BPMMax.(float64) {
+		return fmt.Errorf("validation error: bpm_min can not be greater than bpm_max")
+	}
+
+	if f.Key != nil {
+		val, ok := f.Key.(string)
+		if !ok {
+			return fmt.Errorf("validation error: key filter must be a string")
+		}
+		key, err := NormalizeKey(val)
+		if err != nil {
+			return err
+		}
+		f.Key = key
+	}
+
+	if f.ISRC != nil {
+		val, ok := f.ISRC.(string)
+		if !ok {
+			return fmt.Errorf("validation error: isrc filter must be a string")
+		}
+		isrc, err := NormalizeISRC(val)
+		if err != nil {
+			return err
+		}
+		f.ISRC = isrc
+	}
+
+	if f.CreditedPerson != nil {
+		val, ok := f.CreditedPerson.(string)
+		if !ok {
+			return fmt.Errorf("validation error: credited_person filter must be a string")
+		}
+		f.CreditedPerson = NormalizeName(val)
+	}
+
+	if f.OriginalsOnly != nil {
+		val, ok := f.OriginalsOnly.(bool)
+		if !ok {
+			return fmt.Errorf("validation error: originals_only filter must be a boolean")
+		}
+		if !val {
+			f.OriginalsOnly = nil
+		}
+	}
+
+	if f.Query != nil {
+		val, ok := f.Query.(string)
+		if !ok {
+			return fmt.Errorf("validation error: query filter must be a string")
+		}
+		val = strings.TrimSpace(val)
+		if val == "" {
+			return fmt.Errorf("validation error: query filter can not be empty")
+		}
+		f.Query = val
+	}
+
+	if f.QueryLang != nil {
+		if f.Query == nil {
+			return fmt.Errorf("validation error: query_lang filter requires a query")
+		}
+		val, ok := f.QueryLang.(string)
+		if !ok {
+			return fmt.Errorf("validation error: query_lang filter must be a string")
+		}
+		lang, err := NormalizeLang(val)
+		if err != nil {
+			return err
+		}
+		f.QueryLang = lang
+	}
+
+	if f.Fuzzy != nil {
+		val, ok := f.Fuzzy.(bool)
+		if !ok {
+			return fmt.Errorf("validation error: fuzzy filter must be a boolean")
+		}
+		if !val {
+			f.Fuzzy = nil
+		} else if f.Group == nil && f.Song == nil {
+			return fmt.Errorf("validation error: fuzzy filter requires a group or song filter")
+		}
+	}
+
+	if f.FuzzyThreshold != nil {
+		if f.Fuzzy == nil {
+			return fmt.Errorf("validation error: fuzzy_threshold filter requires fuzzy")
+		}
+		val, err := floatValue(f.FuzzyThreshold, "fuzzy_threshold filter")
+		if err != nil {
+			return err
+		}
+		if val <= 0 || val > 1 {
+			return fmt.Errorf("validation error: fuzzy_threshold must be greater than 0 and not greater than 1")
+		}
+		f.FuzzyThreshold = val
+	}
+
+	if f.Q != nil {
+		val, ok := f.Q.(string)
+		if !ok {
+			return fmt.Errorf("validation error: q filter must be a string")
+		}
+		f.query = val
+		node, err := querylang.Parse(val, queryFields)
+		if err != nil {
+			return err
+		}
+		if err := querylang.Walk(node, normalizeQueryValue); err != nil {
+			return err
+		}
+		f.Q = node
+	}
+
 	if f.ReleaseDateBefore != nil {
 		val, ok := f.ReleaseDateBefore.(string)
 		if !ok {
 			return fmt.Errorf("validation error: release_date_before filter must be a string")
 		}
-		date, err := time.Parse("02.01.2006", val)
+		date, err := ParseReleaseDate(strings.TrimSpace(val), "release_date_before")
 		if err != nil {
-			return fmt.Errorf("invalid release_date_before format: %s, right format '16.09.2021'", val)
+			return err
 		}
 		f.ReleaseDateBefore = date
 	}
@@ -55,12 +291,30 @@ func (f *Filters) Validate() error {
 		if !ok {
 			return fmt.Errorf("validation error: release_date_after filter must be a string")
 		}
-		date, err := time.Parse("02.01.2006", val)
+		date, err := ParseReleaseDate(strings.TrimSpace(val), "release_date_after")
 		if err != nil {
-			return fmt.Errorf("invalid release_date_after format: %s, right format '16.09.2021'", val)
+			return err
 		}
 		f.ReleaseDateAfter = date
 	}
 
 	return nil
 }
+
+// normalizeQueryValue brings the exact values of the q filter to the form they
+// are stored in.
+func normalizeQueryValue(m *querylang.Match) error {
+	var err error
+	switch m.Field {
+	case "tag", "genre":
+		m.Value = strings.ToLower(NormalizeName(m.Value))
+	case "key":
+		m.Value, err = NormalizeKey(m.Value)
+	case "isrc":
+		m.Value, err = NormalizeISRC(m.Value)
+	}
+	if err != nil {
+		return &querylang.SyntaxError{Pos: m.Pos, Msg: strings.TrimPrefix(err.Error(), "validation error: ")}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"music-library/internal/lib/audiotag"
+	"path/filepath"
+	"strings"
+)
+
+// Import asks to import the audio files of a directory inside the configured
+// import directory.
+type Import struct {
+	Dir    string `json:"dir" example:"muse"`
+	DryRun bool   `json:"dry_run" example:"true"`
+}
+
+func (i *Import) Validate() error {
+	i.Dir = filepath.Clean(strings.TrimSpace(i.Dir))
+
+	if !filepath.IsLocal(i.Dir) {
+		return fmt.Errorf("validation error: dir must be a relative path inside the import directory")
+	}
+	return nil
+}
+
+// SongFromTags maps the tags of an audio file onto a song. The first artist
+// is the group and the others are featuring, the artist, title and date are
+// required while lyrics are optional.
+func SongFromTags(tags audiotag.Tags) (SongDB, error) {
+	var group string
+	if len(tags.Artists) > 0 {
+		group = NormalizeName(tags.Artists[0])
+	}
+	song := strings.TrimSpace(tags.Title)
+	date := strings.TrimSpace(tags.Date)
+
+	switch {
+	case group == "":
+		return SongDB{}, errors.New("validation error: artist tag is missing")
+	case song == "":
+		return SongDB{}, errors.New("validation error: title tag is missing")
+	case date == "":
+		return SongDB{}, errors.New("validation error: date tag is missing")
+	}
+
+	// recording times like 2006-07-16T12:00 keep the date only
+	if i := strings.IndexAny(date, "T "); i > 0 {
+		date = date[:i]
+	}
+	releaseDate, err := ParseReleaseDate(date, "date tag")
+	if err != nil {
+		return SongDB{}, err
+	}
+
+	text := NormalizeLyrics(tags.Lyrics)
+	if err := ValidateLyrics(text, "lyrics tag"); err != nil {
+		return SongDB{}, err
+	}
+
+	model := SongDB{
+		Group:       group,
+		Song:        song,
+		ReleaseDate: releaseDate,
+		Text:        text,
+		Featuring:   normalizeArtists(tags.Artists[1:], group),
+	}
+
+	// lengths and tempos out of range are left out rather than failing the file
+	if tags.Duration > 0 && validateDuration(tags.Duration) == nil {
+		model.Duration = &tags.Duration
+	}
+	if tags.BPM > 0 && validateBPM(tags.BPM) == nil {
+		model.BPM = &tags.BPM
+	}
+
+	if tags.ISRC != "" {
+		isrc, err := NormalizeISRC(tags.ISRC)
+		if err != nil {
+			return SongDB{}, err
+		}
+		model.ISRC = &isrc
+	}
+
+	if album := strings.TrimSpace(tags.Album); album != "" {
+		model.Album = &AlbumDB{
+			Title:       album,
+			Artist:      group,
+			ReleaseDate: releaseDate,
+			Track:       tags.Track,
+		}
+	}
+
+	return model, nil
+}
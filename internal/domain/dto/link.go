@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+	"net/url"
+	"strings"
+)
+
+const (
+	ProviderYouTube    = "youtube"
+	ProviderBandcamp   = "bandcamp"
+	ProviderSpotify    = "spotify"
+	ProviderAppleMusic = "apple_music"
+	ProviderSoundCloud = "soundcloud"
+	ProviderLyrics     = "lyrics"
+	ProviderOther      = "other"
+)
+
+// providerHosts maps a registrable domain to the provider it belongs to,
+// subdomains of the listed hosts are matched as well.
+var providerHosts = map[string]string{
+	"youtube.com":     ProviderYouTube,
+	"youtu.be":        ProviderYouTube,
+	"bandcamp.com":    ProviderBandcamp,
+	"spotify.com":     ProviderSpotify,
+	"spotify.link":    ProviderSpotify,
+	"music.apple.com": ProviderAppleMusic,
+	"soundcloud.com":  ProviderSoundCloud,
+	"genius.com":      ProviderLyrics,
+	"musixmatch.com":  ProviderLyrics,
+	"azlyrics.com":    ProviderLyrics,
+	"lyrics.com":      ProviderLyrics,
+}
+
+type Link struct {
+	URL     string  `json:"url" validate:"required" example:"https://muse.bandcamp.com/track/supermassive-black-hole"`
+	Label   *string `json:"label" example:"Bandcamp"`
+	Primary bool    `json:"primary" example:"false"`
+}
+
+func (l *Link) Validate() error {
+	l.URL = strings.TrimSpace(l.URL)
+	if l.Label != nil {
+		val := strings.TrimSpace(*l.Label)
+		l.Label = &val
+	}
+
+	if err := validator.Validate(l); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+
+	if _, err := ParseLink(l.URL); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (l *Link) ToDBModel() (LinkDB, error) {
+	link, err := ParseLink(l.URL)
+	if err != nil {
+		return LinkDB{}, err
+	}
+	link.Label = l.Label
+	link.Primary = l.Primary
+
+	return link, nil
+}
+
+type LinkDB struct {
+	Provider string  `json:"provider"`
+	URL      string  `json:"url"`
+	Label    *string `json:"label"`
+	Primary  bool    `json:"primary"`
+}
+
+// ParseLink validates an absolute http(s) URL and detects its provider from
+// the host.
+func ParseLink(rawURL string) (LinkDB, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return LinkDB{}, fmt.Errorf("validation error: invalid link %q, an absolute http(s) url is expected", rawURL)
+	}
+
+	return LinkDB{
+		Provider: DetectProvider(u.Hostname()),
+		URL:      u.String(),
+	}, nil
+}
+
+func DetectProvider(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	for domain, provider := range providerHosts {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return provider
+		}
+	}
+	return ProviderOther
+}
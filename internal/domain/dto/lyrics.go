@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// UndeterminedLang is the language of original lyrics saved without one.
+const UndeterminedLang = "und"
+
+// MaxLyricsLength and MaxLyricsLines bound the lyrics of a song, longer texts
+// are not lyrics and could not be compared between revisions.
+const (
+	MaxLyricsLength = 100000
+	MaxLyricsLines  = 2000
+)
+
+type Lyrics struct {
+	Text     string `json:"text" validate:"required" example:"Ooh baby, don't you know I suffer?"`
+	Original bool   `json:"original" example:"false"`
+}
+
+func (l *Lyrics) Validate() error {
+	l.Text = NormalizeLyrics(l.Text)
+
+	if err := validator.Validate(l); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return ValidateLyrics(l.Text, "text")
+}
+
+// NormalizeLyrics brings the line endings of the text to the form the
+// original lyrics are stored in, so couplets split the same way in every
+// language.
+func NormalizeLyrics(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.TrimSpace(text)
+}
+
+// ValidateLyrics checks normalized lyrics against MaxLyricsLength characters
+// and MaxLyricsLines lines.
+func ValidateLyrics(text string, field string) error {
+	if utf8.RuneCountInString(text) > MaxLyricsLength {
+		return fmt.Errorf("validation error: %s must not be longer than %d characters", field, MaxLyricsLength)
+	}
+	if strings.Count(text, "\n")+1 > MaxLyricsLines {
+		return fmt.Errorf("validation error: %s must not have more than %d lines", field, MaxLyricsLines)
+	}
+	return nil
+}
+
+// NormalizeLang validates a BCP-47 language tag and returns it in the
+// canonical form, e.g. "EN_us" becomes "en-US".
+func NormalizeLang(lang string) (string, error) {
+	tag, err := language.Parse(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+	if err != nil {
+		return "", fmt.Errorf("validation error: invalid language tag %q", lang)
+	}
+	return tag.String(), nil
+}
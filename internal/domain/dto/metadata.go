@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxDuration = 24 * 60 * 60
+	maxBPM      = 400
+)
+
+var isrcRegexp = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+
+// NormalizeKey converts a musical key such as "c# minor", "Db major" or "Am"
+// into the short notation "C#m", "Db", "Am".
+func NormalizeKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("validation error: key can not be empty")
+	}
+
+	note := strings.ToUpper(key[:1])
+	if !strings.Contains("ABCDEFG", note) {
+		return "", fmt.Errorf("validation error: invalid key %q, expected a note from A to G", key)
+	}
+
+	rest := strings.ToLower(strings.TrimSpace(key[1:]))
+	rest = strings.NewReplacer("♯", "#", "♭", "b").Replace(rest)
+
+	var accidental string
+	if strings.HasPrefix(rest, "#") || strings.HasPrefix(rest, "b") {
+		accidental, rest = rest[:1], strings.TrimSpace(rest[1:])
+	}
+
+	switch rest {
+	case "", "maj", "major":
+		return note + accidental, nil
+	case "m", "min", "minor":
+		return note + accidental + "m", nil
+	default:
+		return "", fmt.Errorf("validation error: invalid key %q, expected major or minor mode", key)
+	}
+}
+
+// NormalizeISRC upper-cases the code and strips the optional hyphens.
+func NormalizeISRC(isrc string) (string, error) {
+	val := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(isrc), "-", ""))
+	if !isrcRegexp.MatchString(val) {
+		return "", fmt.Errorf("validation error: invalid isrc %q, right format 'GB-AHT-06-00123'", isrc)
+	}
+	return val, nil
+}
+
+func validateDuration(duration int) error {
+	if duration <= 0 || duration > maxDuration {
+		return fmt.Errorf("validation error: duration must be from 1 to %d seconds", maxDuration)
+	}
+	return nil
+}
+
+func validateBPM(bpm float64) error {
+	if bpm <= 0 || bpm > maxBPM {
+		return fmt.Errorf("validation error: bpm must be greater than 0 and not greater than %d", maxBPM)
+	}
+	return nil
+}
+
+// intValue converts a decoded JSON number into an int.
+func intValue(value any, name string) (int, error) {
+	val, ok := value.(float64)
+	if !ok || val != float64(int(val)) {
+		return 0, fmt.Errorf("validation error: %s must be an integer", name)
+	}
+	return int(val), nil
+}
+
+func floatValue(value any, name string) (float64, error) {
+	val, ok := value.(float64)
+	if !ok {
+		return 0, fmt.Errorf("validation error: %s must be a number", name)
+	}
+	return val, nil
+}
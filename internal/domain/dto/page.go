@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Counts are how the total of a listing is counted, an estimate comes from
+// the planner statistics and is much cheaper on large listings.
+const (
+	CountExact    = "exact"
+	CountEstimate = "estimate"
+)
+
+// Keyset is the position of a page in the listing, the sort values of the row
+// the page starts after, or ends before with Before.
+type Keyset struct {
+	Values []*string
+	Before bool
+}
+
+// ParseCount checks the count of a listing, exact by default.
+func ParseCount(count string) (string, error) {
+	switch count {
+	case "":
+		return CountExact, nil
+	case CountExact, CountEstimate:
+		return count, nil
+	}
+	return "", fmt.Errorf("validation error: count must be %s or %s, got %q", CountExact, CountEstimate, count)
+}
+
+// Facets are the facets the songs of a listing can be counted by.
+var Facets = []string{"group", "decade", "year", "tag"}
+
+// ParseFacets reads a comma separated list of facets like "group,decade".
+func ParseFacets(facets string) ([]string, error) {
+	if strings.TrimSpace(facets) == "" {
+		return nil, nil
+	}
+
+	var parsed []string
+	for _, part := range strings.Split(facets, ",") {
+		facet := strings.ToLower(strings.TrimSpace(part))
+		if !slices.Contains(Facets, facet) {
+			return nil, fmt.Errorf("validation error: unknown facet %q, allowed are %s", facet, strings.Join(Facets, ", "))
+		}
+		if slices.Contains(parsed, facet) {
+			return nil, fmt.Errorf("validation error: facet %q is repeated", facet)
+		}
+		parsed = append(parsed, facet)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+	"strings"
+)
+
+const (
+	RoleComposer = "composer"
+	RoleLyricist = "lyricist"
+	RoleArranger = "arranger"
+)
+
+type PersonCredit struct {
+	Name string `json:"name" validate:"required" example:"Matthew Bellamy"`
+	Role string `json:"role" validate:"required,oneof=composer lyricist arranger" example:"composer"`
+}
+
+func (c *PersonCredit) Validate() error {
+	c.Name = NormalizeName(c.Name)
+	c.Role = strings.ToLower(strings.TrimSpace(c.Role))
+
+	if err := validator.Validate(c); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+	"strings"
+)
+
+type Playlist struct {
+	Name            string  `json:"name" validate:"required,max=200" example:"Workout"`
+	Description     *string `json:"description" example:"Songs for the gym"`
+	AllowDuplicates bool    `json:"allow_duplicates" example:"false"`
+}
+
+func (p *Playlist) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Description != nil {
+		val := strings.TrimSpace(*p.Description)
+		p.Description = &val
+	}
+
+	if err := validator.Validate(p); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
+
+type UpdatePlaylist struct {
+	ID              int `json:"-"`
+	Name            any `json:"name"`
+	Description     any `json:"description"`
+	AllowDuplicates any `json:"allow_duplicates"`
+}
+
+func (u *UpdatePlaylist) Validate() error {
+	if u.Name == nil && u.Description == nil && u.AllowDuplicates == nil {
+		return fmt.Errorf("validation error: nothing to update")
+	}
+
+	if u.Name != nil {
+		val, ok := u.Name.(string)
+		if !ok {
+			return fmt.Errorf("validation error: name must be a string")
+		}
+		val = strings.TrimSpace(val)
+		if val == "" || len(val) > 200 {
+			return fmt.Errorf("validation error: name must be from 1 to 200 characters")
+		}
+		u.Name = val
+	}
+
+	if u.Description != nil {
+		val, ok := u.Description.(string)
+		if !ok {
+			return fmt.Errorf("validation error: description must be a string")
+		}
+		u.Description = strings.TrimSpace(val)
+	}
+
+	if u.AllowDuplicates != nil {
+		if _, ok := u.AllowDuplicates.(bool); !ok {
+			return fmt.Errorf("validation error: allow_duplicates must be a boolean")
+		}
+	}
+
+	return nil
+}
+
+type AddPlaylistEntry struct {
+	SongID   int `json:"song_id" validate:"required,gt=0" example:"1"`
+	Position int `json:"position" validate:"gte=0" example:"0"`
+}
+
+func (e *AddPlaylistEntry) Validate() error {
+	if err := validator.Validate(e); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
+
+type MovePlaylistEntry struct {
+	Position int `json:"position" validate:"required,gt=0" example:"1"`
+}
+
+func (e *MovePlaylistEntry) Validate() error {
+	if err := validator.Validate(e); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
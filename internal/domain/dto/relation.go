@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+)
+
+type SongRelation struct {
+	OriginalID int    `json:"original_id" validate:"required,gt=0" example:"1"`
+	Relation   string `json:"relation" validate:"required,oneof=cover_of remix_of live_of acoustic_of" example:"cover_of"`
+}
+
+func (r *SongRelation) Validate() error {
+	if err := validator.Validate(r); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
@@ -2,9 +2,9 @@ package dto
 
 import (
 	"fmt"
+	"music-library/internal/lib/partialdate"
 	"music-library/internal/lib/validator"
 	"strings"
-	"time"
 )
 
 type SongRequest struct {
@@ -13,7 +13,7 @@ type SongRequest struct {
 }
 
 func (r *SongRequest) Validate() error {
-	r.Group = strings.TrimSpace(r.Group)
+	r.Group = NormalizeName(r.Group)
 	r.Song = strings.TrimSpace(r.Song)
 
 	if err := validator.Validate(r); err != "" {
@@ -23,38 +23,105 @@ func (r *SongRequest) Validate() error {
 }
 
 type SongDB struct {
-	Group       string    `json:"group"`
-	Song        string    `json:"song"`
-	ReleaseDate time.Time `json:"releaseDate"`
-	Text        string    `json:"text"`
-	Patronymic  string    `json:"patronymic"`
+	Group       string           `json:"group"`
+	Song        string           `json:"song"`
+	ReleaseDate partialdate.Date `json:"releaseDate"`
+	Text        string           `json:"text"`
+	Featuring   []string         `json:"featuring"`
+	Links       []LinkDB         `json:"links"`
+	Album       *AlbumDB         `json:"album"`
+	Duration    *int             `json:"duration"`
+	BPM         *float64         `json:"bpm"`
+	Key         *string          `json:"key"`
+	ISRC        *string          `json:"isrc"`
+	Explicit    *bool            `json:"explicit"`
 }
 
 type Song struct {
-	Group       string `json:"group" validate:"required"`
-	Song        string `json:"song" validate:"required"`
-	ReleaseDate string `json:"releaseDate" validate:"required"`
-	Text        string `json:"text" validate:"required"`
-	Patronymic  string `json:"patronymic" validate:"required"`
+	Group       string     `json:"group" validate:"required"`
+	Song        string     `json:"song" validate:"required"`
+	ReleaseDate string     `json:"releaseDate" validate:"required" example:"2006-07-16"`
+	Text        string     `json:"text" validate:"required"`
+	Patronymic  string     `json:"patronymic" validate:"required"`
+	Featuring   []string   `json:"featuring,omitempty" example:"Kanye West"`
+	Album       *SongAlbum `json:"album,omitempty"`
+	Duration    *int       `json:"duration,omitempty" validate:"omitempty,gt=0,lte=86400" example:"212"`
+	BPM         *float64   `json:"bpm,omitempty" validate:"omitempty,gt=0,lte=400" example:"120"`
+	Key         *string    `json:"key,omitempty" example:"F#m"`
+	ISRC        *string    `json:"isrc,omitempty" example:"GBAHT0600123"`
+	Explicit    *bool      `json:"explicit,omitempty" example:"false"`
 }
 
 func (s *Song) Validate() error {
-	s.Group = strings.TrimSpace(s.Group)
+	s.Group = NormalizeName(s.Group)
 	s.Song = strings.TrimSpace(s.Song)
 	s.ReleaseDate = strings.TrimSpace(s.ReleaseDate)
-	s.Text = strings.TrimSpace(s.Text)
+	s.Text = NormalizeLyrics(s.Text)
 	s.Patronymic = strings.TrimSpace(s.Patronymic)
+	s.Featuring = normalizeArtists(s.Featuring, s.Group)
+	if s.Album != nil {
+		s.Album.Title = strings.TrimSpace(s.Album.Title)
+		s.Album.ReleaseDate = strings.TrimSpace(s.Album.ReleaseDate)
+		s.Album.Type = strings.TrimSpace(s.Album.Type)
+	}
 
 	if err := validator.Validate(s); err != "" {
 		return fmt.Errorf("validation error: %s", err)
 	}
+
+	if err := ValidateLyrics(s.Text, "text"); err != nil {
+		return err
+	}
+
+	if _, err := ParseLink(s.Patronymic); err != nil {
+		return err
+	}
+
+	if s.Key != nil {
+		key, err := NormalizeKey(*s.Key)
+		if err != nil {
+			return err
+		}
+		s.Key = &key
+	}
+
+	if s.ISRC != nil {
+		isrc, err := NormalizeISRC(*s.ISRC)
+		if err != nil {
+			return err
+		}
+		s.ISRC = &isrc
+	}
 	return nil
 }
 
 func (s *Song) ToDBModel() (SongDB, error) {
-	releaseDate, err := time.Parse("02.01.2006", s.ReleaseDate)
+	releaseDate, err := ParseReleaseDate(s.ReleaseDate, "release_date")
 	if err != nil {
-		return SongDB{}, fmt.Errorf("invalid release_date format: %s, right format '16.09.2021'", s.ReleaseDate)
+		return SongDB{}, err
+	}
+
+	link, err := ParseLink(s.Patronymic)
+	if err != nil {
+		return SongDB{}, err
+	}
+	link.Primary = true
+
+	var album *AlbumDB
+	if s.Album != nil {
+		album = &AlbumDB{
+			Title:       s.Album.Title,
+			Artist:      s.Group,
+			ReleaseDate: releaseDate,
+			Type:        s.Album.Type,
+			Track:       s.Album.Track,
+		}
+		if s.Album.ReleaseDate != "" {
+			album.ReleaseDate, err = ParseReleaseDate(s.Album.ReleaseDate, "album releaseDate")
+			if err != nil {
+				return SongDB{}, err
+			}
+		}
 	}
 
 	return SongDB{
@@ -62,7 +129,14 @@ func (s *Song) ToDBModel() (SongDB, error) {
 		Song:        s.Song,
 		ReleaseDate: releaseDate,
 		Text:        s.Text,
-		Patronymic:  s.Patronymic,
+		Featuring:   s.Featuring,
+		Links:       []LinkDB{link},
+		Album:       album,
+		Duration:    s.Duration,
+		BPM:         s.BPM,
+		Key:         s.Key,
+		ISRC:        s.ISRC,
+		Explicit:    s.Explicit,
 	}, nil
 }
 
@@ -73,6 +147,11 @@ type UpdateSong struct {
 	ReleaseDate any `json:"releaseDate"`
 	Text        any `json:"text"`
 	Patronymic  any `json:"patronymic"`
+	Duration    any `json:"duration"`
+	BPM         any `json:"bpm"`
+	Key         any `json:"key"`
+	ISRC        any `json:"isrc"`
+	Explicit    any `json:"explicit"`
 }
 
 func (u *UpdateSong) Validate() error {
@@ -86,6 +165,10 @@ func (u *UpdateSong) Validate() error {
 		if !ok {
 			return fmt.Errorf("validation error: group filter must be a string")
 		}
+		val = NormalizeName(val)
+		if val == "" {
+			return fmt.Errorf("validation error: group can not be empty")
+		}
 		u.Group = val
 	}
 
@@ -102,6 +185,10 @@ func (u *UpdateSong) Validate() error {
 		if !ok {
 			return fmt.Errorf("validation error: text filter must be a string")
 		}
+		val = NormalizeLyrics(val)
+		if err := ValidateLyrics(val, "text"); err != nil {
+			return err
+		}
 		u.Text = val
 	}
 
@@ -110,9 +197,9 @@ func (u *UpdateSong) Validate() error {
 		if !ok {
 			return fmt.Errorf("validation error: release_date filter must be a string")
 		}
-		date, err := time.Parse("02.01.2006", val)
+		date, err := ParseReleaseDate(strings.TrimSpace(val), "release_date")
 		if err != nil {
-			return fmt.Errorf("invalid release_date_before format: %s, right format '16.09.2021'", val)
+			return err
 		}
 		u.ReleaseDate = date
 	}
@@ -122,8 +209,75 @@ func (u *UpdateSong) Validate() error {
 		if !ok {
 			return fmt.Errorf("validation error: patronymic filter must be a string")
 		}
-		u.Patronymic = val
+		link, err := ParseLink(val)
+		if err != nil {
+			return err
+		}
+		link.Primary = true
+		u.Patronymic = link
+	}
+
+	if u.Duration != nil {
+		val, err := intValue(u.Duration, "duration")
+		if err != nil {
+			return err
+		}
+		if err := validateDuration(val); err != nil {
+			return err
+		}
+		u.Duration = val
+	}
+
+	if u.BPM != nil {
+		val, err := floatValue(u.BPM, "bpm")
+		if err != nil {
+			return err
+		}
+		if err := validateBPM(val); err != nil {
+			return err
+		}
+		u.BPM = val
+	}
+
+	if u.Key != nil {
+		val, ok := u.Key.(string)
+		if !ok {
+			return fmt.Errorf("validation error: key must be a string")
+		}
+		key, err := NormalizeKey(val)
+		if err != nil {
+			return err
+		}
+		u.Key = key
+	}
+
+	if u.ISRC != nil {
+		val, ok := u.ISRC.(string)
+		if !ok {
+			return fmt.Errorf("validation error: isrc must be a string")
+		}
+		isrc, err := NormalizeISRC(val)
+		if err != nil {
+			return err
+		}
+		u.ISRC = isrc
+	}
+
+	if u.Explicit != nil {
+		if _, ok := u.Explicit.(bool); !ok {
+			return fmt.Errorf("validation error: explicit must be a boolean")
+		}
 	}
 
 	return nil
 }
+
+// ParseReleaseDate reads a possibly partial release date, name is the field
+// reported in the error.
+func ParseReleaseDate(val string, name string) (partialdate.Date, error) {
+	date, err := partialdate.Parse(val)
+	if err != nil {
+		return partialdate.Date{}, fmt.Errorf("invalid %s format: %s, right formats '2021', '2021-09', '2021-09-16' or '16.09.2021'", name, val)
+	}
+	return date, nil
+}
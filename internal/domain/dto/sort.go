@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortKeys are the keys songs can be sorted by, rank and similarity need the
+// query and fuzzy filters.
+var SortKeys = []string{"id", "group", "song", "release_date", "duration", "bpm", "rank", "similarity"}
+
+type SortKey struct {
+	Key  string
+	Desc bool
+}
+
+// ParseSort reads a sort like "release_date desc, group asc", the direction is
+// ascending by default.
+func ParseSort(sort string, filters Filters) ([]SortKey, error) {
+	if strings.TrimSpace(sort) == "" {
+		return nil, nil
+	}
+
+	var keys []SortKey
+	seen := map[string]bool{}
+	for _, part := range strings.Split(sort, ",") {
+		fields := strings.Fields(strings.ToLower(part))
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("validation error: invalid sort %q, right format 'release_date desc, group asc'", strings.TrimSpace(part))
+		}
+
+		key := SortKey{Key: fields[0]}
+		if !isSortKey(key.Key) {
+			return nil, fmt.Errorf("validation error: unknown sort key %q, allowed are %s", key.Key, strings.Join(SortKeys, ", "))
+		}
+		if seen[key.Key] {
+			return nil, fmt.Errorf("validation error: sort key %q is repeated", key.Key)
+		}
+		seen[key.Key] = true
+
+		if len(fields) == 2 {
+			switch fields[1] {
+			case "asc":
+			case "desc":
+				key.Desc = true
+			default:
+				return nil, fmt.Errorf("validation error: sort direction must be asc or desc, got %q", fields[1])
+			}
+		}
+
+		if key.Key == "rank" && filters.Query == nil {
+			return nil, fmt.Errorf("validation error: sort by rank requires the query filter")
+		}
+		if key.Key == "similarity" && filters.Fuzzy == nil {
+			return nil, fmt.Errorf("validation error: sort by similarity requires the fuzzy filter")
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func isSortKey(key string) bool {
+	for _, k := range SortKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Order returns the order songs are listed in: the sort, without one the most
+// similar and best ranked songs first when searching, and the id to break
+// ties so pages do not overlap.
+func Order(sort []SortKey, filters Filters) []SortKey {
+	order := make([]SortKey, 0, len(sort)+3)
+	order = append(order, sort...)
+	if len(sort) == 0 {
+		if filters.Fuzzy != nil {
+			order = append(order, SortKey{Key: "similarity", Desc: true})
+		}
+		if filters.Query != nil {
+			order = append(order, SortKey{Key: "rank", Desc: true})
+		}
+	}
+
+	for _, key := range order {
+		if key.Key == "id" {
+			return order
+		}
+	}
+	return append(order, SortKey{Key: "id"})
+}
+
+// SortString renders the keys as "release_date desc,id asc".
+func SortString(keys []SortKey) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		direction := "asc"
+		if key.Desc {
+			direction = "desc"
+		}
+		parts = append(parts, key.Key+" "+direction)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"fmt"
+	"music-library/internal/lib/validator"
+	"strings"
+)
+
+type SongTags struct {
+	Tags []string `json:"tags" validate:"required,min=1,dive,required,max=64" example:"workout,licensed-for-ads"`
+}
+
+func (t *SongTags) Validate() error {
+	t.Tags = normalizeLabels(t.Tags, true)
+
+	if err := validator.Validate(t); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
+
+type SongGenres struct {
+	Genres []string `json:"genres" validate:"required,min=1,dive,required,max=64" example:"Alternative Rock"`
+}
+
+func (g *SongGenres) Validate() error {
+	g.Genres = normalizeLabels(g.Genres, false)
+
+	if err := validator.Validate(g); err != "" {
+		return fmt.Errorf("validation error: %s", err)
+	}
+	return nil
+}
+
+// normalizeLabels cleans up tag and genre names and drops case-insensitive
+// duplicates, keeping the first spelling.
+func normalizeLabels(labels []string, lower bool) []string {
+	result := make([]string, 0, len(labels))
+	seen := make(map[string]struct{}, len(labels))
+
+	for _, label := range labels {
+		label = NormalizeName(label)
+		if lower {
+			label = strings.ToLower(label)
+		}
+		if label == "" {
+			continue
+		}
+		key := strings.ToLower(label)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, label)
+	}
+
+	return result
+}
+
+// labelsFilter converts a decoded JSON array into a list of lower-cased names.
+func labelsFilter(value any, name string) ([]string, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("validation error: %s filter must be an array of strings", name)
+	}
+
+	labels := make([]string, 0, len(values))
+	for _, v := range values {
+		label, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("validation error: %s filter must be an array of strings", name)
+		}
+		labels = append(labels, label)
+	}
+
+	labels = normalizeLabels(labels, true)
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("validation error: %s filter can not be empty", name)
+	}
+	return labels, nil
+}
@@ -0,0 +1,18 @@
+package models
+
+type Album struct {
+	ID                   int          `json:"id"`
+	Title                string       `json:"title"`
+	ArtistID             int          `json:"artist_id"`
+	Artist               string       `json:"artist"`
+	ReleaseDate          string       `json:"release_date"`
+	ReleaseDatePrecision string       `json:"release_date_precision"`
+	Type                 *string      `json:"type"`
+	Tracks               []AlbumTrack `json:"tracks"`
+}
+
+type AlbumTrack struct {
+	Position int    `json:"position"`
+	SongID   int    `json:"song_id"`
+	Song     string `json:"song"`
+}
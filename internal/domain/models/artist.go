@@ -0,0 +1,9 @@
+package models
+
+type Artist struct {
+	ID         int     `json:"id"`
+	Name       string  `json:"name"`
+	SortName   *string `json:"sort_name"`
+	Country    *string `json:"country"`
+	FormedYear *int    `json:"formed_year"`
+}
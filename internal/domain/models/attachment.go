@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type Attachment struct {
+	ID           int       `json:"id"`
+	SongID       int       `json:"song_id"`
+	Kind         string    `json:"kind"`
+	Filename     string    `json:"filename"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	HasThumbnail bool      `json:"has_thumbnail"`
+	CreatedAt    time.Time `json:"created_at"`
+	BlobKey      string    `json:"-"`
+	ThumbnailKey *string   `json:"-"`
+}
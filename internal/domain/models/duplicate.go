@@ -0,0 +1,8 @@
+package models
+
+// Duplicates are the songs sharing the normalized group and song name.
+type Duplicates struct {
+	Group string `json:"group"`
+	Song  string `json:"song"`
+	Songs []Song `json:"songs"`
+}
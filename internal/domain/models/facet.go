@@ -0,0 +1,7 @@
+package models
+
+// FacetValue is a value of a facet and how many songs of the listing have it.
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
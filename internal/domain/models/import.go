@@ -0,0 +1,31 @@
+package models
+
+const (
+	ImportNew       = "new"
+	ImportDuplicate = "duplicate"
+	ImportInvalid   = "invalid"
+)
+
+// ImportReport lists the audio files found by an import. In a dry run
+// nothing is saved and the new files have no song ID.
+type ImportReport struct {
+	DryRun     bool         `json:"dry_run"`
+	New        int          `json:"new"`
+	Duplicates int          `json:"duplicates"`
+	Invalid    int          `json:"invalid"`
+	Files      []ImportFile `json:"files"`
+}
+
+// ImportFile is an audio file of the import. A duplicate either points to a
+// song in the library or to an earlier file of the same import.
+type ImportFile struct {
+	Path          string `json:"path"`
+	Status        string `json:"status"`
+	Format        string `json:"format,omitempty"`
+	Group         string `json:"group,omitempty"`
+	Song          string `json:"song,omitempty"`
+	SongID        int    `json:"song_id,omitempty"`
+	DuplicateOf   int    `json:"duplicate_of,omitempty"`
+	DuplicateFile string `json:"duplicate_file,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
@@ -0,0 +1,10 @@
+package models
+
+type Link struct {
+	ID       int     `json:"id"`
+	SongID   int     `json:"song_id"`
+	Provider string  `json:"provider"`
+	URL      string  `json:"url"`
+	Label    *string `json:"label"`
+	Primary  bool    `json:"primary"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type Lyrics struct {
+	Lang      string    `json:"lang"`
+	Original  bool      `json:"original"`
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+type Person struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	SongsCount int    `json:"songs_count"`
+}
+
+type PersonSong struct {
+	Song
+	Roles []string `json:"roles"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type Playlist struct {
+	ID              int             `json:"id"`
+	Name            string          `json:"name"`
+	Description     *string         `json:"description"`
+	AllowDuplicates bool            `json:"allow_duplicates"`
+	EntriesCount    int             `json:"entries_count"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	Entries         []PlaylistEntry `json:"entries,omitempty"`
+}
+
+type PlaylistEntry struct {
+	ID       int       `json:"id"`
+	Position int       `json:"position"`
+	AddedAt  time.Time `json:"added_at"`
+	Song     Song      `json:"song"`
+}
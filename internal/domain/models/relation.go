@@ -0,0 +1,14 @@
+package models
+
+type SongRelation struct {
+	SongID     int    `json:"song_id"`
+	OriginalID int    `json:"original_id"`
+	Relation   string `json:"relation"`
+}
+
+// SongVersions is the connected part of the relation graph the song belongs
+// to, relations point from a version to its original.
+type SongVersions struct {
+	Songs     []Song         `json:"songs"`
+	Relations []SongRelation `json:"relations"`
+}
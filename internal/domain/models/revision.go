@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// SongState is the editable part of a song as it is kept in the revision
+// history.
+type SongState struct {
+	Group       string   `json:"group"`
+	Song        string   `json:"song"`
+	ReleaseDate string   `json:"releaseDate"`
+	Text        string   `json:"text"`
+	Patronymic  string   `json:"patronymic"`
+	Duration    *int     `json:"duration"`
+	BPM         *float64 `json:"bpm"`
+	Key         *string  `json:"key"`
+	ISRC        *string  `json:"isrc"`
+	Explicit    *bool    `json:"explicit"`
+}
+
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+type SongRevision struct {
+	Revision  int                    `json:"revision"`
+	Changes   map[string]FieldChange `json:"changes"`
+	Song      SongState              `json:"song"`
+	RequestID *string                `json:"request_id"`
+	CreatedAt time.Time              `json:"created_at"`
+}
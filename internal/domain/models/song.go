@@ -1,10 +1,41 @@
 package models
 
 type Song struct {
-	ID          int    `json:"id"`
-	Group       string `json:"group"`
-	Song        string `json:"song"`
-	ReleaseDate string `json:"releaseDate"`
-	Text        string `json:"text"`
-	Patronymic  string `json:"patronymic"`
+	ID                   int      `json:"id"`
+	ArtistID             int      `json:"artist_id"`
+	Group                string   `json:"group"`
+	Featuring            []string `json:"featuring"`
+	Producers            []string `json:"producers"`
+	Song                 string   `json:"song"`
+	ReleaseDate          string   `json:"releaseDate"`
+	ReleaseDatePrecision string   `json:"releaseDatePrecision"`
+	Text                 string   `json:"text"`
+	Patronymic           string   `json:"patronymic"`
+	Duration             *int     `json:"duration"`
+	BPM                  *float64 `json:"bpm"`
+	Key                  *string  `json:"key"`
+	ISRC                 *string  `json:"isrc"`
+	Explicit             *bool    `json:"explicit"`
+	Tags                 []string `json:"tags"`
+	Genres               []string `json:"genres"`
+	Rank                 *float64 `json:"rank,omitempty"`
+	Similarity           *float64 `json:"similarity,omitempty"`
+	// SortValues are the values of the song in the order of the listing as
+	// PostgreSQL renders them, the cursors of a page are made of them.
+	SortValues []*string `json:"-"`
+}
+
+// LibraryPage is a page of the library listing. Total counts the songs of the
+// whole listing, Offset is null for a page read by cursor and a cursor is
+// empty when there is no such page. Facets count the songs of the whole
+// listing by the requested facets.
+type LibraryPage struct {
+	Items      []Song                  `json:"items"`
+	Total      int                     `json:"total"`
+	Limit      int                     `json:"limit"`
+	Offset     *int                    `json:"offset"`
+	HasMore    bool                    `json:"has_more"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	PrevCursor string                  `json:"prev_cursor,omitempty"`
+	Facets     map[string][]FacetValue `json:"facets,omitempty"`
 }
@@ -0,0 +1,8 @@
+package models
+
+import "time"
+
+type TrashItem struct {
+	Song
+	DeletedAt time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,331 @@
+package library
+
+import (
+	"context"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Save a new album
+// @Description	Save a new album, the artist is created when it does not exist yet.
+// @Tags			Albums
+// @Accept			json
+// @Produce		json
+// @Param			Album	body		dto.Album			true	"Album information"
+// @Success		201		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/albums [post]
+func (h *Handler) SaveAlbum(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.SaveAlbum"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		var album dto.Album
+		if err := render.Decode(r, &album); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := album.Validate(); err != nil {
+			h.log.Error("validation error in album info", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		id, err := h.service.SaveAlbum(ctx, album, requestID)
+		if err != nil {
+			h.log.Error("failed to save album", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, http.StatusCreated, map[string]any{
+			"detail": "new album successfully saved",
+			"id":     id,
+		})
+	}
+}
+
+// @Summary		Get albums
+// @Description	Get albums ordered by release date.
+// @Tags			Albums
+// @Accept			json
+// @Produce		json
+// @Param			artist_id	query		int					false	"artistID"
+// @Param			limit		query		int					true	"limit"		default(10)
+// @Param			offset		query		int					true	"offset"	default(0)
+// @Success		200			{array}		models.Album		"success response"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/albums [get]
+func (h *Handler) GetAlbums(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetAlbums"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		artistID, err := strconv.Atoi(r.URL.Query().Get("artist_id"))
+		if err != nil || artistID < 0 {
+			artistID = 0
+		}
+
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		albums, err := h.service.GetAlbums(ctx, artistID, limit, offset, requestID)
+		if err != nil {
+			h.log.Error("failed to get albums", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, albums)
+	}
+}
+
+// @Summary		Get album
+// @Description	Get album with its ordered tracklist.
+// @Tags			Albums
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"albumID"
+// @Success		200	{object}	models.Album		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/albums/{id} [get]
+func (h *Handler) GetAlbum(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetAlbum"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		albumID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || albumID <= 0 {
+			h.log.Error("invalid album ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid album ID")
+			return
+		}
+
+		album, err := h.service.GetAlbum(ctx, albumID, requestID)
+		if err != nil {
+			h.log.Error("failed to get album", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, album)
+	}
+}
+
+// @Summary		Delete album
+// @Description	Delete album, the songs stay in library.
+// @Tags			Albums
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"albumID"
+// @Success		200	{object}	map[string]any		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/albums/{id} [delete]
+func (h *Handler) DeleteAlbum(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.DeleteAlbum"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		albumID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || albumID <= 0 {
+			h.log.Error("invalid album ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid album ID")
+			return
+		}
+
+		if err := h.service.DeleteAlbum(ctx, albumID, requestID); err != nil {
+			h.log.Error("failed to delete album", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"album_id": albumID,
+			"detail":   "album was successfully deleted",
+		})
+	}
+}
+
+// @Summary		Add album track
+// @Description	Attach a song to the album, position 0 or one past the last track appends it to the end.
+// @Tags			Albums
+// @Accept			json
+// @Produce		json
+// @Param			id				path		int					true	"albumID"
+// @Param			AddAlbumTrack	body		dto.AddAlbumTrack	true	"Track information"
+// @Success		201				{object}	map[string]any		"success response"
+// @Failure		500				{object}	map[string]string	"failure response"
+// @Failure		400				{object}	map[string]string	"failure response"
+// @Router			/albums/{id}/tracks [post]
+func (h *Handler) AddAlbumTrack(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.AddAlbumTrack"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		albumID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || albumID <= 0 {
+			h.log.Error("invalid album ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid album ID")
+			return
+		}
+
+		var track dto.AddAlbumTrack
+		if err := render.Decode(r, &track); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := track.Validate(); err != nil {
+			h.log.Error("validation error in track info", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		position, err := h.service.AddAlbumTrack(ctx, albumID, track, requestID)
+		if err != nil {
+			h.log.Error("failed to add album track", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, http.StatusCreated, map[string]any{
+			"album_id": albumID,
+			"song_id":  track.SongID,
+			"position": position,
+			"detail":   "track successfully added to album",
+		})
+	}
+}
+
+// @Summary		Reorder album tracks
+// @Description	Set the album tracklist order, song_ids must list every track of the album.
+// @Tags			Albums
+// @Accept			json
+// @Produce		json
+// @Param			id					path		int						true	"albumID"
+// @Param			ReorderAlbumTracks	body		dto.ReorderAlbumTracks	true	"New tracks order"
+// @Success		200					{object}	map[string]any			"success response"
+// @Failure		500					{object}	map[string]string		"failure response"
+// @Failure		400					{object}	map[string]string		"failure response"
+// @Router			/albums/{id}/tracks [put]
+func (h *Handler) ReorderAlbumTracks(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.ReorderAlbumTracks"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		albumID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || albumID <= 0 {
+			h.log.Error("invalid album ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid album ID")
+			return
+		}
+
+		var tracks dto.ReorderAlbumTracks
+		if err := render.Decode(r, &tracks); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := tracks.Validate(); err != nil {
+			h.log.Error("validation error in tracks order", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		if err := h.service.ReorderAlbumTracks(ctx, albumID, tracks, requestID); err != nil {
+			h.log.Error("failed to reorder album tracks", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"album_id": albumID,
+			"detail":   "album tracks successfully reordered",
+		})
+	}
+}
+
+// @Summary		Remove album track
+// @Description	Detach a song from the album, the following tracks move up.
+// @Tags			Albums
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"albumID"
+// @Param			songID	path		int					true	"songID"
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/albums/{id}/tracks/{songID} [delete]
+func (h *Handler) RemoveAlbumTrack(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.RemoveAlbumTrack"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		albumID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || albumID <= 0 {
+			h.log.Error("invalid album ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid album ID")
+			return
+		}
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "songID"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		if err := h.service.RemoveAlbumTrack(ctx, albumID, songID, requestID); err != nil {
+			h.log.Error("failed to remove album track", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"album_id": albumID,
+			"song_id":  songID,
+			"detail":   "track successfully removed from album",
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Save a new artist
+// @Description	Save a new artist into library.
+// @Tags			Artists
+// @Accept			json
+// @Produce		json
+// @Param			Artist	body		dto.Artist			true	"Artist information"
+// @Success		201		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/artists [post]
+func (h *Handler) SaveArtist(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.SaveArtist"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		var artist dto.Artist
+		if err := render.Decode(r, &artist); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := artist.Validate(); err != nil {
+			h.log.Error("validation error in artist info", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		id, err := h.service.SaveArtist(ctx, artist, requestID)
+		if err != nil {
+			h.log.Error("failed to save artist", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, http.StatusCreated, map[string]any{
+			"detail": "new artist successfully saved",
+			"id":     id,
+		})
+	}
+}
+
+// @Summary		Get artists
+// @Description	Get artists ordered by sort name.
+// @Tags			Artists
+// @Accept			json
+// @Produce		json
+// @Param			limit	query		int					true	"limit"		default(10)
+// @Param			offset	query		int					true	"offset"	default(0)
+// @Success		200		{array}		models.Artist		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/artists [get]
+func (h *Handler) GetArtists(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetArtists"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		artists, err := h.service.GetArtists(ctx, limit, offset, requestID)
+		if err != nil {
+			h.log.Error("failed to get artists", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, artists)
+	}
+}
+
+// @Summary		Get artist
+// @Description	Get artist by ID.
+// @Tags			Artists
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"artistID"
+// @Success		200	{object}	models.Artist		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/artists/{id} [get]
+func (h *Handler) GetArtist(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetArtist"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		artistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || artistID <= 0 {
+			h.log.Error("invalid artist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid artist ID")
+			return
+		}
+
+		artist, err := h.service.GetArtist(ctx, artistID, requestID)
+		if err != nil {
+			h.log.Error("failed to get artist", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, artist)
+	}
+}
+
+// @Summary		Update artist
+// @Description	Update artist
+// @Tags			Artists
+// @Accept			json
+// @Produce		json
+// @Param			id				path		int					true	"artistID"
+// @Param			UpdateArtist	body		dto.UpdateArtist	true	"Artist information"
+// @Success		200				{object}	map[string]any		"success response"
+// @Failure		500				{object}	map[string]string	"failure response"
+// @Failure		400				{object}	map[string]string	"failure response"
+// @Failure		409				{object}	map[string]any		"a song of the artist is already in the library"
+// @Router			/artists/{id} [patch]
+func (h *Handler) UpdateArtist(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.UpdateArtist"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		artistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || artistID <= 0 {
+			h.log.Error("invalid artist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid artist ID")
+			return
+		}
+
+		var updateModel dto.UpdateArtist
+		if err := render.Decode(r, &updateModel); err != nil {
+			h.log.Error("failed to decode update model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode update model")
+			return
+		}
+		updateModel.ID = artistID
+
+		if err := updateModel.Validate(); err != nil {
+			h.log.Error("validation error in update artist info", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		if err := h.service.UpdateArtist(ctx, updateModel, requestID); err != nil {
+			h.log.Error("failed to update artist", sl.Err(err))
+			var dupErr *storage.DuplicateSongError
+			if errors.As(err, &dupErr) {
+				handlers.ErrorResponse(w, r, http.StatusConflict, map[string]any{
+					"message": dupErr.Error(),
+					"id":      dupErr.ID,
+				})
+				return
+			}
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"artist_id": artistID,
+			"detail":    "artist successfully updated",
+		})
+	}
+}
+
+// @Summary		Delete artist
+// @Description	Delete artist without songs
+// @Tags			Artists
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"artistID"
+// @Success		200	{object}	map[string]any		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/artists/{id} [delete]
+func (h *Handler) DeleteArtist(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.DeleteArtist"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		artistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || artistID <= 0 {
+			h.log.Error("invalid artist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid artist ID")
+			return
+		}
+
+		if err := h.service.DeleteArtist(ctx, artistID, requestID); err != nil {
+			h.log.Error("failed to delete artist", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"artist_id": artistID,
+			"detail":    "artist was successfully deleted",
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/storage"
+	"music-library/internal/storage/blob"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// multipartOverhead is what the multipart form may take on top of the file,
+// the boundaries, the part headers and the skipped fields.
+const multipartOverhead = 1 << 20
+
+// @Summary		Upload attachment
+// @Description	Upload artwork, sheet music or another file for the song as the file field of a multipart form. The type is sniffed from the content, JPEG, PNG, GIF, WebP and PDF are accepted. Images get a thumbnail.
+// @Tags			Attachments
+// @Accept			multipart/form-data
+// @Produce		json
+// @Param			id		path		int					true	"songID"
+// @Param			kind	query		string				false	"artwork, sheet_music or other, picked from the type by default"
+// @Param			file	formData	file				true	"file"
+// @Success		201		{object}	models.Attachment	"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		422		{object}	map[string]string	"failure response"
+// @Failure		415		{object}	map[string]string	"failure response"
+// @Failure		413		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/song/{id}/attachments [post]
+func (h *Handler) SaveAttachment(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.SaveAttachment"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, h.maxUpload+multipartOverhead)
+
+		mr, err := r.MultipartReader()
+		if err != nil {
+			h.log.Error("invalid multipart form", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "expected a multipart/form-data body")
+			return
+		}
+
+		// the file is streamed to the blob store, other fields are skipped
+		var part io.Reader
+		attachment := dto.Attachment{Kind: r.URL.Query().Get("kind")}
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				h.log.Error("failed to read multipart form", sl.Err(err))
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					handlers.ErrorResponse(w, r, http.StatusRequestEntityTooLarge, "file is too large")
+					return
+				}
+				handlers.ErrorResponse(w, r, 400, "failed to read multipart form")
+				return
+			}
+			if p.FormName() == "file" {
+				attachment.Filename = p.FileName()
+				part = p
+				break
+			}
+		}
+		if part == nil {
+			h.log.Error("file field is missing")
+			handlers.ErrorResponse(w, r, 400, "file field is missing")
+			return
+		}
+
+		if err := attachment.Validate(); err != nil {
+			h.log.Error("validation error in attachment", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		att, err := h.service.SaveAttachment(ctx, songID, attachment, part, requestID)
+		if err != nil {
+			h.log.Error("failed to save attachment", sl.Err(err))
+			var maxErr *http.MaxBytesError
+			switch {
+			case errors.Is(err, blob.ErrTooLarge), errors.As(err, &maxErr):
+				handlers.ErrorResponse(w, r, http.StatusRequestEntityTooLarge, "file is too large")
+			case errors.Is(err, storage.ErrUnsupportedMediaType):
+				handlers.ErrorResponse(w, r, http.StatusUnsupportedMediaType, err.Error())
+			default:
+				handlers.ErrorResponse(w, r, 400, err.Error())
+			}
+			return
+		}
+
+		handlers.SuccessResponse(w, r, http.StatusCreated, att)
+	}
+}
+
+// @Summary		Get attachments
+// @Description	Get the attachments of the song.
+// @Tags			Attachments
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"songID"
+// @Success		200	{array}		models.Attachment	"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/song/{id}/attachments [get]
+func (h *Handler) GetAttachments(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetAttachments"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		attachments, err := h.service.GetAttachments(ctx, songID, requestID)
+		if err != nil {
+			h.log.Error("failed to get attachments", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, attachments)
+	}
+}
+
+// @Summary		Download attachment
+// @Description	Download the attachment or its thumbnail. Range requests and conditional requests with the ETag are supported.
+// @Tags			Attachments
+// @Produce		octet-stream
+// @Param			id			path		int					true	"attachmentID"
+// @Param			thumbnail	query		bool				false	"download the thumbnail"	default(false)
+// @Success		200			{file}		file				"attachment"
+// @Success		206			{file}		file				"part of the attachment"
+// @Success		304			{string}	string				"not modified"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		404			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/attachments/{id} [get]
+func (h *Handler) DownloadAttachment(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.DownloadAttachment"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		attachmentID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || attachmentID <= 0 {
+			h.log.Error("invalid attachment ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid attachment ID")
+			return
+		}
+
+		thumb, _ := strconv.ParseBool(r.URL.Query().Get("thumbnail"))
+
+		att, file, err := h.service.OpenAttachment(ctx, attachmentID, thumb, requestID)
+		if err != nil {
+			h.log.Error("failed to open attachment", sl.Err(err))
+			if errors.Is(err, blob.ErrNotFound) {
+				handlers.ErrorResponse(w, r, http.StatusNotFound, "attachment data not found")
+				return
+			}
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+		defer file.Close()
+
+		contentType, etag, filename := att.ContentType, fmt.Sprintf(`"%s"`, att.SHA256), att.Filename
+		if thumb {
+			contentType, etag = "image/jpeg", fmt.Sprintf(`"%s-thumb"`, att.SHA256)
+			filename = strings.TrimSuffix(filename, "."+extension(filename)) + "-thumb.jpg"
+		}
+
+		disposition := "attachment"
+		if strings.HasPrefix(contentType, "image/") || contentType == "application/pdf" {
+			disposition = "inline"
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "private, max-age=0, must-revalidate")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": filename}))
+
+		// ServeContent answers Range, If-Range and If-None-Match itself
+		http.ServeContent(w, r, filename, att.CreatedAt, file)
+	}
+}
+
+// @Summary		Delete attachment
+// @Description	Delete the attachment together with its thumbnail.
+// @Tags			Attachments
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"attachmentID"
+// @Success		200	{object}	map[string]any		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/attachments/{id} [delete]
+func (h *Handler) DeleteAttachment(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.DeleteAttachment"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		attachmentID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || attachmentID <= 0 {
+			h.log.Error("invalid attachment ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid attachment ID")
+			return
+		}
+
+		if err := h.service.DeleteAttachment(ctx, attachmentID, requestID); err != nil {
+			h.log.Error("failed to delete attachment", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"attachment_id": attachmentID,
+			"detail":        "attachment successfully deleted",
+		})
+	}
+}
+
+func extension(filename string) string {
+	if i := strings.LastIndexByte(filename, '.'); i >= 0 {
+		return filename[i+1:]
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Credit artist on song
+// @Description	Credit artist on the song as featuring or producer, unknown artists are created.
+// @Tags			Artists
+// @Accept			json
+// @Produce		json
+// @Param			id			path		int					true	"songID"
+// @Param			SongCredit	body		dto.SongCredit		true	"Credit"
+// @Success		200			{object}	map[string]any		"success response"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/song/{id}/artists [post]
+func (h *Handler) AddSongCredit(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.AddSongCredit"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		var credit dto.SongCredit
+		if err := render.Decode(r, &credit); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := credit.Validate(); err != nil {
+			h.log.Error("validation error in credit", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		artistID, err := h.service.AddSongCredit(ctx, songID, credit, requestID)
+		if err != nil {
+			h.log.Error("failed to add song credit", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id":   songID,
+			"artist_id": artistID,
+			"role":      credit.Role,
+			"detail":    "artist successfully credited",
+		})
+	}
+}
+
+// @Summary		Remove artist credit
+// @Description	Remove featuring or producer credit of the artist from the song.
+// @Tags			Artists
+// @Accept			json
+// @Produce		json
+// @Param			id			path		int					true	"songID"
+// @Param			artistID	path		int					true	"artistID"
+// @Param			role		path		string				true	"role"	Enums(featuring, producer)
+// @Success		200			{object}	map[string]any		"success response"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/song/{id}/artists/{artistID}/{role} [delete]
+func (h *Handler) RemoveSongCredit(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.RemoveSongCredit"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		artistID, err := strconv.Atoi(chi.URLParam(r, "artistID"))
+		if err != nil || artistID <= 0 {
+			h.log.Error("invalid artist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid artist ID")
+			return
+		}
+
+		role := strings.ToLower(chi.URLParam(r, "role"))
+		if role != dto.RoleFeaturing && role != dto.RoleProducer && role != dto.RolePrimary {
+			h.log.Error("invalid role", slog.String("role", role))
+			handlers.ErrorResponse(w, r, 400, "invalid role")
+			return
+		}
+
+		if err := h.service.RemoveSongCredit(ctx, songID, artistID, role, requestID); err != nil {
+			h.log.Error("failed to remove song credit", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id":   songID,
+			"artist_id": artistID,
+			"role":      role,
+			"detail":    "artist credit successfully removed",
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package library
+
+import (
+	"context"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Get duplicates
+// @Description	Get songs that have the same group and song name ignoring case, whitespace and diacritics, grouped together. Songs in the trash are left out.
+// @Tags			Duplicates
+// @Accept			json
+// @Produce		json
+// @Param			limit	query		int					true	"limit"		default(10)
+// @Param			offset	query		int					true	"offset"	default(0)
+// @Success		200		{array}		models.Duplicates	"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/duplicates [get]
+func (h *Handler) GetDuplicates(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetDuplicates"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		duplicates, err := h.service.GetDuplicates(ctx, limit, offset, requestID)
+		if err != nil {
+			h.log.Error("failed to get duplicates", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, duplicates)
+	}
+}
+
+// @Summary		Merge song
+// @Description	Fold the song into another one: links, tags, genres, attachments, playlist entries, album tracks, credits, translations, synced lyrics and relations move to the target and the song is deleted for good.
+// @Tags			Duplicates
+// @Accept			json
+// @Produce		json
+// @Param			id			path		int					true	"songID"
+// @Param			MergeSong	body		dto.MergeSong		true	"Target song"
+// @Success		200			{object}	map[string]any		"success response"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		422			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/song/{id}/merge [post]
+func (h *Handler) MergeSong(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.MergeSong"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		var merge dto.MergeSong
+		if err := render.Decode(r, &merge); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := merge.Validate(); err != nil {
+			h.log.Error("validation error in merge info", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		if err := h.service.MergeSong(ctx, songID, merge, requestID); err != nil {
+			h.log.Error("failed to merge song", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"into":    merge.Into,
+			"detail":  "song successfully merged",
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package library
+
+import (
+	"context"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Import songs
+// @Description	Walk a directory inside the import directory and save a song for every MP3, FLAC and MP4 file from its ID3, Vorbis comment or MP4 tags. Files without an artist, title or date are reported as invalid and songs already in the library as duplicates. A dry run saves nothing. Large directories are better imported with the import command.
+// @Tags			Import
+// @Accept			json
+// @Produce		json
+// @Param			Import	body		dto.Import			true	"Directory to import"
+// @Success		200		{object}	models.ImportReport	"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		422		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/admin/import [post]
+func (h *Handler) ImportSongs(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.ImportSongs"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		var model dto.Import
+		if err := render.Decode(r, &model); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := model.Validate(); err != nil {
+			h.log.Error("validation error in import", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		report, err := h.service.ImportSongs(ctx, model, requestID)
+		if err != nil {
+			h.log.Error("failed to import songs", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, report)
+	}
+}
@@ -2,14 +2,24 @@ package library
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"music-library/internal/domain/dto"
 	"music-library/internal/domain/models"
 	"music-library/internal/handlers"
+	"music-library/internal/lib/cursor"
+	"music-library/internal/lib/diff"
 	"music-library/internal/lib/logger/sl"
 	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/lrc"
+	"music-library/internal/lib/querylang"
+	"music-library/internal/lib/sections"
+	"music-library/internal/storage"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -19,29 +29,147 @@ import (
 type Handler struct {
 	log     *slog.Logger
 	service LibraryService
+	// maxUpload is the largest attachment the blob store takes, in bytes
+	maxUpload int64
 }
 
 type LibraryService interface {
 	SaveSong(ctx context.Context, model dto.SongRequest, requestID string) (int, error)
-	GetLibrary(ctx context.Context, filters dto.Filters, limit int, offset int, requestID string) ([]models.Song, error)
-	GetSongText(ctx context.Context, songID int, couplet int, requestID string) (string, error)
-	DeleteSong(ctx context.Context, songID int, requestID string) error
+	GetLibrary(ctx context.Context, filters dto.Filters, sort []dto.SortKey, limit int, offset int, pageCursor string, count string, facets []string, requestID string) (models.LibraryPage, error)
+	GetSongText(ctx context.Context, songID int, section string, index int, lang string, requestID string) (sections.Section, string, error)
+	DeleteSong(ctx context.Context, songID int, purge bool, requestID string) error
 	UpdateSong(ctx context.Context, updateModel dto.UpdateSong, requestID string) error
+
+	SaveArtist(ctx context.Context, model dto.Artist, requestID string) (int, error)
+	GetArtists(ctx context.Context, limit int, offset int, requestID string) ([]models.Artist, error)
+	GetArtist(ctx context.Context, artistID int, requestID string) (models.Artist, error)
+	UpdateArtist(ctx context.Context, updateModel dto.UpdateArtist, requestID string) error
+	DeleteArtist(ctx context.Context, artistID int, requestID string) error
+
+	SaveAlbum(ctx context.Context, model dto.Album, requestID string) (int, error)
+	GetAlbums(ctx context.Context, artistID int, limit int, offset int, requestID string) ([]models.Album, error)
+	GetAlbum(ctx context.Context, albumID int, requestID string) (models.Album, error)
+	DeleteAlbum(ctx context.Context, albumID int, requestID string) error
+	AddAlbumTrack(ctx context.Context, albumID int, track dto.AddAlbumTrack, requestID string) (int, error)
+	ReorderAlbumTracks(ctx context.Context, albumID int, tracks dto.ReorderAlbumTracks, requestID string) error
+	RemoveAlbumTrack(ctx context.Context, albumID int, songID int, requestID string) error
+
+	AddSongTags(ctx context.Context, songID int, tags dto.SongTags, requestID string) error
+	RemoveSongTag(ctx context.Context, songID int, tag string, requestID string) error
+	AddSongGenres(ctx context.Context, songID int, genres dto.SongGenres, requestID string) error
+	RemoveSongGenre(ctx context.Context, songID int, genre string, requestID string) error
+
+	SavePlaylist(ctx context.Context, model dto.Playlist, requestID string) (int, error)
+	GetPlaylists(ctx context.Context, limit int, offset int, requestID string) ([]models.Playlist, error)
+	GetPlaylist(ctx context.Context, playlistID int, requestID string) (models.Playlist, error)
+	UpdatePlaylist(ctx context.Context, updateModel dto.UpdatePlaylist, requestID string) error
+	DeletePlaylist(ctx context.Context, playlistID int, requestID string) error
+	AddPlaylistEntry(ctx context.Context, playlistID int, entry dto.AddPlaylistEntry, requestID string) (int, int, error)
+	RemovePlaylistEntry(ctx context.Context, playlistID int, entryID int, requestID string) error
+	MovePlaylistEntry(ctx context.Context, playlistID int, entryID int, move dto.MovePlaylistEntry, requestID string) (int, error)
+	GetSongLinks(ctx context.Context, songID int, requestID string) ([]models.Link, error)
+	AddSongLink(ctx context.Context, songID int, link dto.Link, requestID string) (int, error)
+	RemoveSongLink(ctx context.Context, songID int, linkID int, requestID string) error
+	GetSongRevisions(ctx context.Context, songID int, requestID string) ([]models.SongRevision, error)
+	GetSongTextDiff(ctx context.Context, songID int, from int, to int, requestID string) ([]diff.Line, error)
+	RestoreSongRevision(ctx context.Context, songID int, revision int, requestID string) error
+	GetTrash(ctx context.Context, limit int, offset int, requestID string) ([]models.TrashItem, error)
+	RestoreSong(ctx context.Context, songID int, requestID string) error
+	AddSongCredit(ctx context.Context, songID int, credit dto.SongCredit, requestID string) (int, error)
+	RemoveSongCredit(ctx context.Context, songID int, artistID int, role string, requestID string) error
+	GetPeople(ctx context.Context, limit int, offset int, requestID string) ([]models.Person, error)
+	GetPersonSongs(ctx context.Context, personID int, limit int, offset int, requestID string) ([]models.PersonSong, error)
+	AddPersonCredit(ctx context.Context, songID int, credit dto.PersonCredit, requestID string) (int, error)
+	RemovePersonCredit(ctx context.Context, songID int, personID int, role string, requestID string) error
+	GetLyrics(ctx context.Context, songID int, requestID string) ([]models.Lyrics, error)
+	SaveLyrics(ctx context.Context, songID int, lang string, lyrics dto.Lyrics, requestID string) error
+	DeleteTranslation(ctx context.Context, songID int, lang string, requestID string) error
+	GetSyncedLyrics(ctx context.Context, songID int, lang string, requestID string) ([]lrc.Line, string, error)
+	SaveSyncedLyrics(ctx context.Context, songID int, lang string, lines []lrc.Line, requestID string) error
+	DeleteSyncedLyrics(ctx context.Context, songID int, lang string, requestID string) error
+	GetDuplicates(ctx context.Context, limit int, offset int, requestID string) ([]models.Duplicates, error)
+	MergeSong(ctx context.Context, songID int, merge dto.MergeSong, requestID string) error
+	ImportSongs(ctx context.Context, model dto.Import, requestID string) (models.ImportReport, error)
+	SaveAttachment(ctx context.Context, songID int, attachment dto.Attachment, r io.Reader, requestID string) (models.Attachment, error)
+	GetAttachments(ctx context.Context, songID int, requestID string) ([]models.Attachment, error)
+	OpenAttachment(ctx context.Context, attachmentID int, thumb bool, requestID string) (models.Attachment, io.ReadSeekCloser, error)
+	DeleteAttachment(ctx context.Context, attachmentID int, requestID string) error
+	LinkSongs(ctx context.Context, songID int, relation dto.SongRelation, requestID string) error
+	UnlinkSongs(ctx context.Context, songID int, originalID int, requestID string) error
+	GetSongVersions(ctx context.Context, songID int, requestID string) (models.SongVersions, error)
 }
 
-func NewHandler(log *slog.Logger, service LibraryService) *Handler {
-	return &Handler{log: log, service: service}
+func NewHandler(log *slog.Logger, service LibraryService, maxUpload int64) *Handler {
+	return &Handler{log: log, service: service, maxUpload: maxUpload}
 }
 
-func AddHandler(ctx context.Context, log *slog.Logger, service LibraryService) func(r chi.Router) {
-	handler := NewHandler(log, service)
+func AddHandler(ctx context.Context, log *slog.Logger, service LibraryService, maxUpload int64) func(r chi.Router) {
+	handler := NewHandler(log, service, maxUpload)
 
 	return func(r chi.Router) {
 		r.Post("/save", handler.SaveSong(ctx))
 		r.Post("/get", handler.GetLibrary(ctx))
 		r.Get("/song-text", handler.GetSongText(ctx))
 		r.Delete("/song/{id}", handler.DeleteSong(ctx))
+		r.Post("/song/{id}/restore", handler.RestoreSong(ctx))
+		r.Get("/trash", handler.GetTrash(ctx))
+		r.Get("/duplicates", handler.GetDuplicates(ctx))
+		r.Post("/song/{id}/merge", handler.MergeSong(ctx))
+		r.Get("/song/{id}/attachments", handler.GetAttachments(ctx))
+		r.Post("/song/{id}/attachments", handler.SaveAttachment(ctx))
+		r.Get("/attachments/{id}", handler.DownloadAttachment(ctx))
+		r.Delete("/attachments/{id}", handler.DeleteAttachment(ctx))
+		r.Post("/admin/import", handler.ImportSongs(ctx))
 		r.Patch("/update", handler.UpdateSong(ctx))
+
+		r.Post("/song/{id}/tags", handler.AddSongTags(ctx))
+		r.Delete("/song/{id}/tags/{tag}", handler.RemoveSongTag(ctx))
+		r.Post("/song/{id}/genres", handler.AddSongGenres(ctx))
+		r.Delete("/song/{id}/genres/{genre}", handler.RemoveSongGenre(ctx))
+		r.Get("/song/{id}/links", handler.GetSongLinks(ctx))
+		r.Post("/song/{id}/links", handler.AddSongLink(ctx))
+		r.Delete("/song/{id}/links/{linkID}", handler.RemoveSongLink(ctx))
+		r.Get("/song/{id}/revisions", handler.GetSongRevisions(ctx))
+		r.Get("/song/{id}/revisions/diff", handler.GetSongTextDiff(ctx))
+		r.Post("/song/{id}/revisions/{rev}/restore", handler.RestoreSongRevision(ctx))
+		r.Get("/song/{id}/lyrics", handler.GetLyrics(ctx))
+		r.Put("/song/{id}/lyrics/{lang}", handler.SaveLyrics(ctx))
+		r.Delete("/song/{id}/lyrics/{lang}", handler.DeleteTranslation(ctx))
+		r.Put("/song/{id}/lyrics/{lang}/synced", handler.SaveSyncedLyrics(ctx))
+		r.Delete("/song/{id}/lyrics/{lang}/synced", handler.DeleteSyncedLyrics(ctx))
+		r.Post("/song/{id}/artists", handler.AddSongCredit(ctx))
+		r.Delete("/song/{id}/artists/{artistID}/{role}", handler.RemoveSongCredit(ctx))
+		r.Post("/song/{id}/people", handler.AddPersonCredit(ctx))
+		r.Delete("/song/{id}/people/{personID}/{role}", handler.RemovePersonCredit(ctx))
+		r.Post("/song/{id}/relations", handler.LinkSongs(ctx))
+		r.Delete("/song/{id}/relations/{originalID}", handler.UnlinkSongs(ctx))
+		r.Get("/song/{id}/versions", handler.GetSongVersions(ctx))
+
+		r.Get("/artists", handler.GetArtists(ctx))
+		r.Post("/artists", handler.SaveArtist(ctx))
+		r.Get("/artists/{id}", handler.GetArtist(ctx))
+		r.Patch("/artists/{id}", handler.UpdateArtist(ctx))
+		r.Delete("/artists/{id}", handler.DeleteArtist(ctx))
+
+		r.Get("/people", handler.GetPeople(ctx))
+		r.Get("/people/{id}/songs", handler.GetPersonSongs(ctx))
+
+		r.Get("/albums", handler.GetAlbums(ctx))
+		r.Post("/albums", handler.SaveAlbum(ctx))
+		r.Get("/albums/{id}", handler.GetAlbum(ctx))
+		r.Delete("/albums/{id}", handler.DeleteAlbum(ctx))
+		r.Post("/albums/{id}/tracks", handler.AddAlbumTrack(ctx))
+		r.Put("/albums/{id}/tracks", handler.ReorderAlbumTracks(ctx))
+		r.Delete("/albums/{id}/tracks/{songID}", handler.RemoveAlbumTrack(ctx))
+
+		r.Get("/playlists", handler.GetPlaylists(ctx))
+		r.Post("/playlists", handler.SavePlaylist(ctx))
+		r.Get("/playlists/{id}", handler.GetPlaylist(ctx))
+		r.Patch("/playlists/{id}", handler.UpdatePlaylist(ctx))
+		r.Delete("/playlists/{id}", handler.DeletePlaylist(ctx))
+		r.Post("/playlists/{id}/entries", handler.AddPlaylistEntry(ctx))
+		r.Delete("/playlists/{id}/entries/{entryID}", handler.RemovePlaylistEntry(ctx))
+		r.Post("/playlists/{id}/entries/{entryID}/move", handler.MovePlaylistEntry(ctx))
 	}
 }
 
@@ -54,6 +182,7 @@ func AddHandler(ctx context.Context, log *slog.Logger, service LibraryService) f
 // @Success		200			{object}	map[string]any		"success response"
 // @Failure		500			{object}	map[string]string	"failure response"
 // @Failure		400			{object}	map[string]string	"failure response"
+// @Failure		409			{object}	map[string]any		"song is already in the library"
 // @Router			/save [post]
 func (h *Handler) SaveSong(ctx context.Context) http.HandlerFunc {
 	const op = "handlers.library.SaveSong"
@@ -77,6 +206,14 @@ func (h *Handler) SaveSong(ctx context.Context) http.HandlerFunc {
 		id, err := h.service.SaveSong(ctx, song, requestID)
 		if err != nil {
 			h.log.Error("failed to save song", sl.Err(err))
+			var dupErr *storage.DuplicateSongError
+			if errors.As(err, &dupErr) {
+				handlers.ErrorResponse(w, r, http.StatusConflict, map[string]any{
+					"message": dupErr.Error(),
+					"id":      dupErr.ID,
+				})
+				return
+			}
 			handlers.ErrorResponse(w, r, http.StatusInternalServerError, "failed to save song")
 			return
 		}
@@ -89,16 +226,25 @@ func (h *Handler) SaveSong(ctx context.Context) http.HandlerFunc {
 }
 
 // @Summary		Get songs from library
-// @Description	Get songs from library.
+// @Description	Get songs from library. The query filter is a full-text search in titles and lyrics with the web search syntax: quoted phrases, OR and -word. Its results are ordered by rank. Each song is searched in the language of its lyrics, query_lang parses the query in the given language instead and is faster. With fuzzy the group and song filters match similar names by trigram similarity, tolerating typos, and results are ordered by similarity; fuzzy_threshold overrides the configured minimum similarity. The q filter is a query like group:muse OR group:radiohead -song:live year:2000..2010 combined with the other filters; its fields are group, song, text, album, person, tag, genre, key, isrc, year, duration, bpm and date, terms without a field match the group or song name. A syntax error answers 422 with its position. Songs are ordered by the sort keys, names in alphabet order of every script, empty values last and the id breaks ties; without a sort by similarity and rank when searching. Pages can be read by offset or by cursor: the X-Next-Cursor and X-Prev-Cursor headers and the envelope hold the cursors of the pages around this one, which are only valid with the same sort. The songs come in an envelope with the total of the listing, count=estimate takes it from the planner statistics which is faster on large listings; envelope=false returns the bare array without a total. With facets the envelope also counts the songs of the listing by group, decade, year and tag, the most frequent groups and tags and every decade and year. The Link header has the first, last, previous and next pages, by cursor when the page was read by cursor.
 // @Tags			API
 // @Accept			json
 // @Produce		json
-// @Param			Filters	body		dto.Filters			true	"Song information"
-// @Param			limit	query		int					true	"limit"		default(10)
-// @Param			offset	query		int					true	"offset"	default(0)
-// @Success		200		{array}		models.Song			"success response"
-// @Failure		500		{object}	map[string]string	"failure response"
-// @Failure		400		{object}	map[string]string	"failure response"
+// @Param			Filters		body		dto.Filters			true	"Song information"
+// @Param			sort		query		string				false	"comma separated keys with an optional asc or desc: id, group, song, release_date, duration, bpm, rank, similarity"
+// @Param			limit		query		int					true	"limit"		default(10)
+// @Param			offset		query		int					true	"offset"	default(0)
+// @Param			cursor		query		string				false	"cursor of the page from X-Next-Cursor or X-Prev-Cursor, instead of offset"
+// @Param			count		query		string				false	"how the total is counted"	Enums(exact, estimate)	default(exact)
+// @Param			envelope	query		bool				false	"false returns the bare array of songs"	default(true)
+// @Param			facets		query		string				false	"comma separated facets to count: group, decade, year, tag"
+// @Success		200			{object}	models.LibraryPage	"success response"
+// @Header			200			{string}	X-Next-Cursor		"cursor of the next page, missing on the last page"
+// @Header			200			{string}	X-Prev-Cursor		"cursor of the previous page, missing on the first page"
+// @Header			200			{string}	Link				"RFC 8288 links to the first, last, prev and next pages"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		422			{object}	map[string]any		"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
 // @Router			/get [post]
 func (h *Handler) GetLibrary(ctx context.Context) http.HandlerFunc {
 	const op = "handlers.library.GetLibrary"
@@ -117,6 +263,21 @@ func (h *Handler) GetLibrary(ctx context.Context) http.HandlerFunc {
 
 		if err := filters.Validate(); err != nil {
 			h.log.Error("validation error in filters", sl.Err(err))
+			var syntaxErr *querylang.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				handlers.ErrorResponse(w, r, 422, map[string]any{
+					"position": syntaxErr.Pos,
+					"message":  syntaxErr.Msg,
+				})
+				return
+			}
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		sort, err := dto.ParseSort(r.URL.Query().Get("sort"), filters)
+		if err != nil {
+			h.log.Error("validation error in sort", sl.Err(err))
 			handlers.ErrorResponse(w, r, 422, err.Error())
 			return
 		}
@@ -131,24 +292,123 @@ func (h *Handler) GetLibrary(ctx context.Context) http.HandlerFunc {
 			offset = 0
 		}
 
-		songs, err := h.service.GetLibrary(ctx, filters, limit, offset, requestID)
+		pageCursor := r.URL.Query().Get("cursor")
+		if pageCursor != "" && offset > 0 {
+			h.log.Error("cursor with offset")
+			handlers.ErrorResponse(w, r, 422, "validation error: cursor can not be used with offset")
+			return
+		}
+
+		envelope := true
+		if value := r.URL.Query().Get("envelope"); value != "" {
+			envelope, err = strconv.ParseBool(value)
+			if err != nil {
+				h.log.Error("validation error in envelope", sl.Err(err))
+				handlers.ErrorResponse(w, r, 422, "validation error: envelope must be true or false")
+				return
+			}
+		}
+
+		count, err := dto.ParseCount(r.URL.Query().Get("count"))
+		if err != nil {
+			h.log.Error("validation error in count", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+		if !envelope {
+			count = ""
+		}
+
+		facets, err := dto.ParseFacets(r.URL.Query().Get("facets"))
+		if err != nil {
+			h.log.Error("validation error in facets", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+		if len(facets) > 0 && !envelope {
+			h.log.Error("facets without envelope")
+			handlers.ErrorResponse(w, r, 422, "validation error: facets need the envelope")
+			return
+		}
+
+		page, err := h.service.GetLibrary(ctx, filters, sort, limit, offset, pageCursor, count, facets, requestID)
 		if err != nil {
 			h.log.Error("failed to get library", sl.Err(err))
+			if errors.Is(err, cursor.ErrInvalid) {
+				handlers.ErrorResponse(w, r, 422, err.Error())
+				return
+			}
 			handlers.ErrorResponse(w, r, 400, err.Error())
 			return
 		}
 
-		handlers.SuccessResponse(w, r, 200, songs)
+		if page.NextCursor != "" {
+			w.Header().Set("X-Next-Cursor", page.NextCursor)
+		}
+		if page.PrevCursor != "" {
+			w.Header().Set("X-Prev-Cursor", page.PrevCursor)
+		}
+		w.Header().Set("Link", pageLinks(r, page, count != ""))
+
+		if !envelope {
+			handlers.SuccessResponse(w, r, 200, page.Items)
+			return
+		}
+		handlers.SuccessResponse(w, r, 200, page)
+	}
+}
+
+// pageLinks returns the RFC 8288 links to the pages around the page, keeping
+// the query of the request. A page read by cursor links its neighbours by
+// cursor, the last page is only known with the total.
+func pageLinks(r *http.Request, page models.LibraryPage, withTotal bool) string {
+	link := func(rel string, key string, value string) string {
+		q := r.URL.Query()
+		q.Del("offset")
+		q.Del("cursor")
+		q.Set(key, value)
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, r.URL.Path, q.Encode(), rel)
+	}
+
+	links := []string{link("first", "offset", "0")}
+	if withTotal {
+		last := 0
+		if page.Total > 0 {
+			last = (page.Total - 1) / page.Limit * page.Limit
+		}
+		links = append(links, link("last", "offset", strconv.Itoa(last)))
+	}
+
+	if page.Offset == nil {
+		if page.PrevCursor != "" {
+			links = append(links, link("prev", "cursor", page.PrevCursor))
+		}
+		if page.NextCursor != "" {
+			links = append(links, link("next", "cursor", page.NextCursor))
+		}
+		return strings.Join(links, ", ")
+	}
+
+	if offset := *page.Offset; offset > 0 {
+		links = append(links, link("prev", "offset", strconv.Itoa(max(offset-page.Limit, 0))))
 	}
+	if page.HasMore {
+		links = append(links, link("next", "offset", strconv.Itoa(*page.Offset+page.Limit)))
+	}
+	return strings.Join(links, ", ")
 }
 
 // @Summary		Get song text
-// @Description	Get song text
+// @Description	Get a section of the song text in the requested language, falls back to the original lyrics when there is no translation. A section is picked by type and index or by its position with couplet, without both the whole text is returned. With format lrc or json the time-synced lines are returned instead.
 // @Tags			API
 // @Accept			json
-// @Produce		json
+// @Produce		json,plain
 // @Param			id		query		int					true	"songID"
-// @Param			couplet	query		int					true	"couplet"	default(1)
+// @Param			couplet	query		int					false	"section position, the whole text by default"
+// @Param			section	query		string				false	"section type"											Enums(intro, verse, pre-chorus, chorus, post-chorus, bridge, interlude, outro, other)
+// @Param			index	query		int					false	"index of the section among the sections of its type"	default(1)
+// @Param			lang	query		string				false	"BCP-47 language tag, the original lyrics by default"
+// @Param			format	query		string				false	"text, lrc or json"	default(text)
 // @Success		200		{object}	map[string]any		"success response"
 // @Failure		500		{object}	map[string]string	"failure response"
 // @Failure		400		{object}	map[string]string	"failure response"
@@ -173,7 +433,57 @@ func (h *Handler) GetSongText(ctx context.Context) http.HandlerFunc {
 			couplet = 0
 		}
 
-		text, err := h.service.GetSongText(ctx, songID, couplet, requestID)
+		section := strings.ToLower(r.URL.Query().Get("section"))
+		index := couplet
+		if section != "" {
+			if !sections.IsType(section) {
+				h.log.Error("invalid section", slog.String("section", section))
+				handlers.ErrorResponse(w, r, 400, "invalid section, must be one of "+strings.Join(sections.Types, ", "))
+				return
+			}
+			index, err = strconv.Atoi(r.URL.Query().Get("index"))
+			if err != nil || index <= 0 {
+				index = 1
+			}
+		}
+
+		var lang string
+		if val := r.URL.Query().Get("lang"); val != "" {
+			lang, err = dto.NormalizeLang(val)
+			if err != nil {
+				h.log.Error("invalid lang", sl.Err(err))
+				handlers.ErrorResponse(w, r, 400, "invalid lang")
+				return
+			}
+		}
+
+		format := r.URL.Query().Get("format")
+		switch format {
+		case "", "text":
+		case "lrc", "json":
+			lines, syncedLang, err := h.service.GetSyncedLyrics(ctx, songID, lang, requestID)
+			if err != nil {
+				h.log.Error("failed to get synced lyrics", sl.Err(err))
+				handlers.ErrorResponse(w, r, 400, err.Error())
+				return
+			}
+			if format == "lrc" {
+				handlers.TextResponse(w, r, 200, lrc.Format(lines))
+				return
+			}
+			handlers.SuccessResponse(w, r, 200, map[string]any{
+				"song_id": songID,
+				"lang":    syncedLang,
+				"lines":   lines,
+			})
+			return
+		default:
+			h.log.Error("invalid format", slog.String("format", format))
+			handlers.ErrorResponse(w, r, 400, "invalid format, must be one of text, lrc, json")
+			return
+		}
+
+		sec, textLang, err := h.service.GetSongText(ctx, songID, section, index, lang, requestID)
 		if err != nil {
 			h.log.Error("failed to get song text", sl.Err(err))
 			handlers.ErrorResponse(w, r, 400, err.Error())
@@ -182,21 +492,26 @@ func (h *Handler) GetSongText(ctx context.Context) http.HandlerFunc {
 
 		handlers.SuccessResponse(w, r, 200, map[string]any{
 			"song_id": songID,
-			"couplet": couplet,
-			"text":    text,
+			"couplet": sec.Position,
+			"section": sec.Type,
+			"index":   sec.Index,
+			"label":   sec.Label,
+			"lang":    textLang,
+			"text":    sec.Text,
 		})
 	}
 }
 
 // @Summary		Delete song
-// @Description	Delete song
+// @Description	Move song to the trash, with purge the song is deleted for good.
 // @Tags			API
 // @Accept			json
 // @Produce		json
-// @Param			id	path		int					true	"songID"
-// @Success		200	{object}	map[string]any		"success response"
-// @Failure		500	{object}	map[string]string	"failure response"
-// @Failure		400	{object}	map[string]string	"failure response"
+// @Param			id		path		int					true	"songID"
+// @Param			purge	query		bool				false	"delete for good"	default(false)
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
 // @Router			/song/{id} [delete]
 func (h *Handler) DeleteSong(ctx context.Context) http.HandlerFunc {
 	const op = "handlers.library.DeleteSong"
@@ -213,16 +528,27 @@ func (h *Handler) DeleteSong(ctx context.Context) http.HandlerFunc {
 			return
 		}
 
-		err = h.service.DeleteSong(ctx, songID, requestID)
+		purge, err := strconv.ParseBool(r.URL.Query().Get("purge"))
+		if err != nil {
+			purge = false
+		}
+
+		err = h.service.DeleteSong(ctx, songID, purge, requestID)
 		if err != nil {
 			h.log.Error("failed to delete song", sl.Err(err))
 			handlers.ErrorResponse(w, r, 400, err.Error())
 			return
 		}
 
+		detail := "song was successfully moved to trash"
+		if purge {
+			detail = "song was successfully deleted"
+		}
+
 		handlers.SuccessResponse(w, r, 200, map[string]any{
 			"song_id": songID,
-			"detail":  "song was successfully deleted",
+			"purged":  purge,
+			"detail":  detail,
 		})
 	}
 }
@@ -236,6 +562,7 @@ func (h *Handler) DeleteSong(ctx context.Context) http.HandlerFunc {
 // @Success		200			{object}	map[string]any		"success response"
 // @Failure		500			{object}	map[string]string	"failure response"
 // @Failure		400			{object}	map[string]string	"failure response"
+// @Failure		409			{object}	map[string]any		"song is already in the library"
 // @Router			/update [patch]
 func (h *Handler) UpdateSong(ctx context.Context) http.HandlerFunc {
 	const op = "handlers.library.UpdateSong"
@@ -260,6 +587,14 @@ func (h *Handler) UpdateSong(ctx context.Context) http.HandlerFunc {
 
 		if err := h.service.UpdateSong(ctx, updateModel, requestID); err != nil {
 			h.log.Error("failed to update song", sl.Err(err))
+			var dupErr *storage.DuplicateSongError
+			if errors.As(err, &dupErr) {
+				handlers.ErrorResponse(w, r, http.StatusConflict, map[string]any{
+					"message": dupErr.Error(),
+					"id":      dupErr.ID,
+				})
+				return
+			}
 			handlers.ErrorResponse(w, r, 400, err.Error())
 			return
 		}
@@ -0,0 +1,151 @@
+package library
+
+import (
+	"context"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Get song links
+// @Description	Get external links of the song, the primary link goes first.
+// @Tags			Links
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"songID"
+// @Success		200	{array}		models.Link			"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/song/{id}/links [get]
+func (h *Handler) GetSongLinks(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetSongLinks"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		links, err := h.service.GetSongLinks(ctx, songID, requestID)
+		if err != nil {
+			h.log.Error("failed to get song links", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, links)
+	}
+}
+
+// @Summary		Add song link
+// @Description	Add external link to the song, the provider is detected from the url. Adding an existing url updates its label and primary flag.
+// @Tags			Links
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"songID"
+// @Param			Link	body		dto.Link			true	"Link"
+// @Success		201		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/song/{id}/links [post]
+func (h *Handler) AddSongLink(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.AddSongLink"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		var link dto.Link
+		if err := render.Decode(r, &link); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := link.Validate(); err != nil {
+			h.log.Error("validation error in link", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		id, err := h.service.AddSongLink(ctx, songID, link, requestID)
+		if err != nil {
+			h.log.Error("failed to add song link", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, http.StatusCreated, map[string]any{
+			"song_id": songID,
+			"id":      id,
+			"detail":  "link successfully added",
+		})
+	}
+}
+
+// @Summary		Remove song link
+// @Description	Remove external link from the song, when the primary link is removed the oldest remaining one becomes primary.
+// @Tags			Links
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"songID"
+// @Param			linkID	path		int					true	"linkID"
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/song/{id}/links/{linkID} [delete]
+func (h *Handler) RemoveSongLink(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.RemoveSongLink"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		linkID, err := strconv.Atoi(chi.URLParam(r, "linkID"))
+		if err != nil || linkID <= 0 {
+			h.log.Error("invalid link ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid link ID")
+			return
+		}
+
+		if err := h.service.RemoveSongLink(ctx, songID, linkID, requestID); err != nil {
+			h.log.Error("failed to remove song link", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"link_id": linkID,
+			"detail":  "link successfully removed",
+		})
+	}
+}
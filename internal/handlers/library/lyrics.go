@@ -0,0 +1,290 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"io"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/lrc"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// maxLRCSize limits the uploaded LRC body.
+const maxLRCSize = 1 << 20
+
+// @Summary		Get song lyrics
+// @Description	Get the original lyrics and all translations of the song.
+// @Tags			Lyrics
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"songID"
+// @Success		200	{array}		models.Lyrics		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/song/{id}/lyrics [get]
+func (h *Handler) GetLyrics(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetLyrics"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		lyrics, err := h.service.GetLyrics(ctx, songID, requestID)
+		if err != nil {
+			h.log.Error("failed to get lyrics", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, lyrics)
+	}
+}
+
+// @Summary		Save song lyrics
+// @Description	Add or replace the translation in the given language. With original the text replaces the song text and the original lyrics take the language over.
+// @Tags			Lyrics
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"songID"
+// @Param			lang	path		string				true	"BCP-47 language tag"
+// @Param			Lyrics	body		dto.Lyrics			true	"Lyrics"
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/song/{id}/lyrics/{lang} [put]
+func (h *Handler) SaveLyrics(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.SaveLyrics"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		lang, err := dto.NormalizeLang(chi.URLParam(r, "lang"))
+		if err != nil {
+			h.log.Error("invalid lang", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid lang")
+			return
+		}
+
+		var lyrics dto.Lyrics
+		if err := render.Decode(r, &lyrics); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := lyrics.Validate(); err != nil {
+			h.log.Error("validation error in lyrics", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		if err := h.service.SaveLyrics(ctx, songID, lang, lyrics, requestID); err != nil {
+			h.log.Error("failed to save lyrics", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id":  songID,
+			"lang":     lang,
+			"original": lyrics.Original,
+			"detail":   "lyrics successfully saved",
+		})
+	}
+}
+
+// @Summary		Delete translation
+// @Description	Delete the translation in the given language, the original lyrics can not be deleted.
+// @Tags			Lyrics
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"songID"
+// @Param			lang	path		string				true	"BCP-47 language tag"
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/song/{id}/lyrics/{lang} [delete]
+func (h *Handler) DeleteTranslation(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.DeleteTranslation"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		lang, err := dto.NormalizeLang(chi.URLParam(r, "lang"))
+		if err != nil {
+			h.log.Error("invalid lang", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid lang")
+			return
+		}
+
+		if err := h.service.DeleteTranslation(ctx, songID, lang, requestID); err != nil {
+			h.log.Error("failed to delete translation", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"lang":    lang,
+			"detail":  "translation successfully deleted",
+		})
+	}
+}
+
+// @Summary		Save synced lyrics
+// @Description	Replace the time-synced lines of the song in the given language with the LRC body, enhanced LRC word timings are kept. Timestamps must not go backwards.
+// @Tags			Lyrics
+// @Accept			plain
+// @Produce		json
+// @Param			id		path		int					true	"songID"
+// @Param			lang	path		string				true	"BCP-47 language tag"
+// @Param			LRC		body		string				true	"LRC"
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		422		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/song/{id}/lyrics/{lang}/synced [put]
+func (h *Handler) SaveSyncedLyrics(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.SaveSyncedLyrics"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		lang, err := dto.NormalizeLang(chi.URLParam(r, "lang"))
+		if err != nil {
+			h.log.Error("invalid lang", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid lang")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxLRCSize+1))
+		if err != nil {
+			h.log.Error("failed to read body", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to read body")
+			return
+		}
+		if len(body) > maxLRCSize {
+			h.log.Error("lrc body is too large")
+			handlers.ErrorResponse(w, r, 413, "lrc body is too large")
+			return
+		}
+
+		lyrics, err := lrc.Parse(string(body))
+		if err != nil {
+			var parseErr *lrc.ParseError
+			if errors.As(err, &parseErr) {
+				h.log.Error("invalid lrc", sl.Err(err))
+				handlers.ErrorResponse(w, r, 422, map[string]any{
+					"line":    parseErr.Line,
+					"message": parseErr.Msg,
+				})
+				return
+			}
+			h.log.Error("failed to parse lrc", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		if err := h.service.SaveSyncedLyrics(ctx, songID, lang, lyrics.Lines, requestID); err != nil {
+			h.log.Error("failed to save synced lyrics", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"lang":    lang,
+			"lines":   len(lyrics.Lines),
+			"detail":  "synced lyrics successfully saved",
+		})
+	}
+}
+
+// @Summary		Delete synced lyrics
+// @Description	Delete the time-synced lines of the song in the given language.
+// @Tags			Lyrics
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"songID"
+// @Param			lang	path		string				true	"BCP-47 language tag"
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/song/{id}/lyrics/{lang}/synced [delete]
+func (h *Handler) DeleteSyncedLyrics(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.DeleteSyncedLyrics"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		lang, err := dto.NormalizeLang(chi.URLParam(r, "lang"))
+		if err != nil {
+			h.log.Error("invalid lang", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid lang")
+			return
+		}
+
+		if err := h.service.DeleteSyncedLyrics(ctx, songID, lang, requestID); err != nil {
+			h.log.Error("failed to delete synced lyrics", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"lang":    lang,
+			"detail":  "synced lyrics successfully deleted",
+		})
+	}
+}
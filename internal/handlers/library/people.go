@@ -0,0 +1,215 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Get people
+// @Description	Get songwriters, composers, lyricists and arrangers credited on songs.
+// @Tags			People
+// @Accept			json
+// @Produce		json
+// @Param			limit	query		int					true	"limit"		default(10)
+// @Param			offset	query		int					true	"offset"	default(0)
+// @Success		200		{array}		models.Person		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/people [get]
+func (h *Handler) GetPeople(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetPeople"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		people, err := h.service.GetPeople(ctx, limit, offset, requestID)
+		if err != nil {
+			h.log.Error("failed to get people", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, people)
+	}
+}
+
+// @Summary		Get person songs
+// @Description	Get songs the person is credited on across all groups, with the roles of the person.
+// @Tags			People
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"personID"
+// @Param			limit	query		int					true	"limit"		default(10)
+// @Param			offset	query		int					true	"offset"	default(0)
+// @Success		200		{array}		models.PersonSong	"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/people/{id}/songs [get]
+func (h *Handler) GetPersonSongs(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetPersonSongs"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		personID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || personID <= 0 {
+			h.log.Error("invalid person ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid person ID")
+			return
+		}
+
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		songs, err := h.service.GetPersonSongs(ctx, personID, limit, offset, requestID)
+		if err != nil {
+			h.log.Error("failed to get person songs", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, songs)
+	}
+}
+
+// @Summary		Credit person on song
+// @Description	Credit person on the song as composer, lyricist or arranger, unknown people are created.
+// @Tags			People
+// @Accept			json
+// @Produce		json
+// @Param			id				path		int					true	"songID"
+// @Param			PersonCredit	body		dto.PersonCredit	true	"Credit"
+// @Success		200				{object}	map[string]any		"success response"
+// @Failure		500				{object}	map[string]string	"failure response"
+// @Failure		400				{object}	map[string]string	"failure response"
+// @Router			/song/{id}/people [post]
+func (h *Handler) AddPersonCredit(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.AddPersonCredit"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		var credit dto.PersonCredit
+		if err := render.Decode(r, &credit); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := credit.Validate(); err != nil {
+			h.log.Error("validation error in credit", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		personID, err := h.service.AddPersonCredit(ctx, songID, credit, requestID)
+		if err != nil {
+			h.log.Error("failed to add person credit", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id":   songID,
+			"person_id": personID,
+			"role":      credit.Role,
+			"detail":    "person successfully credited",
+		})
+	}
+}
+
+// @Summary		Remove person credit
+// @Description	Remove credit of the person with the given role from the song.
+// @Tags			People
+// @Accept			json
+// @Produce		json
+// @Param			id			path		int					true	"songID"
+// @Param			personID	path		int					true	"personID"
+// @Param			role		path		string				true	"role"	Enums(composer, lyricist, arranger)
+// @Success		200			{object}	map[string]any		"success response"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/song/{id}/people/{personID}/{role} [delete]
+func (h *Handler) RemovePersonCredit(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.RemovePersonCredit"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		personID, err := strconv.Atoi(chi.URLParam(r, "personID"))
+		if err != nil || personID <= 0 {
+			h.log.Error("invalid person ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid person ID")
+			return
+		}
+
+		role := strings.ToLower(chi.URLParam(r, "role"))
+		if role != dto.RoleComposer && role != dto.RoleLyricist && role != dto.RoleArranger {
+			h.log.Error("invalid role", slog.String("role", role))
+			handlers.ErrorResponse(w, r, 400, "invalid role")
+			return
+		}
+
+		if err := h.service.RemovePersonCredit(ctx, songID, personID, role, requestID); err != nil {
+			h.log.Error("failed to remove person credit", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id":   songID,
+			"person_id": personID,
+			"role":      role,
+			"detail":    "person credit successfully removed",
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Save a new playlist
+// @Description	Save a new playlist.
+// @Tags			Playlists
+// @Accept			json
+// @Produce		json
+// @Param			Playlist	body		dto.Playlist		true	"Playlist information"
+// @Success		201			{object}	map[string]any		"success response"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/playlists [post]
+func (h *Handler) SavePlaylist(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.SavePlaylist"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		var playlist dto.Playlist
+		if err := render.Decode(r, &playlist); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := playlist.Validate(); err != nil {
+			h.log.Error("validation error in playlist info", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		id, err := h.service.SavePlaylist(ctx, playlist, requestID)
+		if err != nil {
+			h.log.Error("failed to save playlist", sl.Err(err))
+			handlers.ErrorResponse(w, r, http.StatusInternalServerError, "failed to save playlist")
+			return
+		}
+
+		handlers.SuccessResponse(w, r, http.StatusCreated, map[string]any{
+			"detail": "new playlist successfully saved",
+			"id":     id,
+		})
+	}
+}
+
+// @Summary		Get playlists
+// @Description	Get playlists without entries.
+// @Tags			Playlists
+// @Accept			json
+// @Produce		json
+// @Param			limit	query		int					true	"limit"		default(10)
+// @Param			offset	query		int					true	"offset"	default(0)
+// @Success		200		{array}		models.Playlist		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/playlists [get]
+func (h *Handler) GetPlaylists(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetPlaylists"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		playlists, err := h.service.GetPlaylists(ctx, limit, offset, requestID)
+		if err != nil {
+			h.log.Error("failed to get playlists", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, playlists)
+	}
+}
+
+// @Summary		Get playlist
+// @Description	Get playlist with ordered entries hydrated with songs.
+// @Description	Songs in the trash are left out and the positions count only the entries shown, a restored song returns to its place.
+// @Tags			Playlists
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"playlistID"
+// @Success		200	{object}	models.Playlist		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/playlists/{id} [get]
+func (h *Handler) GetPlaylist(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetPlaylist"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		playlistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || playlistID <= 0 {
+			h.log.Error("invalid playlist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid playlist ID")
+			return
+		}
+
+		playlist, err := h.service.GetPlaylist(ctx, playlistID, requestID)
+		if err != nil {
+			h.log.Error("failed to get playlist", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, playlist)
+	}
+}
+
+// @Summary		Update playlist
+// @Description	Update playlist
+// @Tags			Playlists
+// @Accept			json
+// @Produce		json
+// @Param			id				path		int					true	"playlistID"
+// @Param			UpdatePlaylist	body		dto.UpdatePlaylist	true	"Playlist information"
+// @Success		200				{object}	map[string]any		"success response"
+// @Failure		500				{object}	map[string]string	"failure response"
+// @Failure		400				{object}	map[string]string	"failure response"
+// @Router			/playlists/{id} [patch]
+func (h *Handler) UpdatePlaylist(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.UpdatePlaylist"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		playlistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || playlistID <= 0 {
+			h.log.Error("invalid playlist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid playlist ID")
+			return
+		}
+
+		var updateModel dto.UpdatePlaylist
+		if err := render.Decode(r, &updateModel); err != nil {
+			h.log.Error("failed to decode update model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode update model")
+			return
+		}
+		updateModel.ID = playlistID
+
+		if err := updateModel.Validate(); err != nil {
+			h.log.Error("validation error in update playlist info", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		if err := h.service.UpdatePlaylist(ctx, updateModel, requestID); err != nil {
+			h.log.Error("failed to update playlist", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"playlist_id": playlistID,
+			"detail":      "playlist successfully updated",
+		})
+	}
+}
+
+// @Summary		Delete playlist
+// @Description	Delete playlist with all its entries.
+// @Tags			Playlists
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"playlistID"
+// @Success		200	{object}	map[string]any		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/playlists/{id} [delete]
+func (h *Handler) DeletePlaylist(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.DeletePlaylist"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		playlistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || playlistID <= 0 {
+			h.log.Error("invalid playlist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid playlist ID")
+			return
+		}
+
+		if err := h.service.DeletePlaylist(ctx, playlistID, requestID); err != nil {
+			h.log.Error("failed to delete playlist", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"playlist_id": playlistID,
+			"detail":      "playlist was successfully deleted",
+		})
+	}
+}
+
+// @Summary		Add playlist entry
+// @Description	Append a song to the playlist or insert it at the given position.
+// @Description	Position 0 appends the song. Adding a song twice fails with 409 unless the playlist allows duplicates.
+// @Tags			Playlists
+// @Accept			json
+// @Produce		json
+// @Param			id					path		int						true	"playlistID"
+// @Param			AddPlaylistEntry	body		dto.AddPlaylistEntry	true	"Entry information"
+// @Success		201					{object}	map[string]any			"success response"
+// @Failure		500					{object}	map[string]string		"failure response"
+// @Failure		409					{object}	map[string]string		"failure response"
+// @Failure		400					{object}	map[string]string		"failure response"
+// @Router			/playlists/{id}/entries [post]
+func (h *Handler) AddPlaylistEntry(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.AddPlaylistEntry"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		playlistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || playlistID <= 0 {
+			h.log.Error("invalid playlist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid playlist ID")
+			return
+		}
+
+		var entry dto.AddPlaylistEntry
+		if err := render.Decode(r, &entry); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := entry.Validate(); err != nil {
+			h.log.Error("validation error in entry info", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		id, position, err := h.service.AddPlaylistEntry(ctx, playlistID, entry, requestID)
+		if err != nil {
+			h.log.Error("failed to add playlist entry", sl.Err(err))
+			if errors.Is(err, storage.ErrSongInPlaylist) {
+				handlers.ErrorResponse(w, r, http.StatusConflict, err.Error())
+				return
+			}
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, http.StatusCreated, map[string]any{
+			"playlist_id": playlistID,
+			"entry_id":    id,
+			"position":    position,
+			"detail":      "song successfully added to playlist",
+		})
+	}
+}
+
+// @Summary		Remove playlist entry
+// @Description	Remove entry from the playlist, the following entries move up.
+// @Tags			Playlists
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"playlistID"
+// @Param			entryID	path		int					true	"entryID"
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/playlists/{id}/entries/{entryID} [delete]
+func (h *Handler) RemovePlaylistEntry(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.RemovePlaylistEntry"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		playlistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || playlistID <= 0 {
+			h.log.Error("invalid playlist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid playlist ID")
+			return
+		}
+
+		entryID, err := strconv.Atoi(chi.URLParam(r, "entryID"))
+		if err != nil || entryID <= 0 {
+			h.log.Error("invalid entry ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid entry ID")
+			return
+		}
+
+		if err := h.service.RemovePlaylistEntry(ctx, playlistID, entryID, requestID); err != nil {
+			h.log.Error("failed to remove playlist entry", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"playlist_id": playlistID,
+			"entry_id":    entryID,
+			"detail":      "entry successfully removed from playlist",
+		})
+	}
+}
+
+// @Summary		Move playlist entry
+// @Description	Move entry to the given position, the entries in between shift accordingly.
+// @Tags			Playlists
+// @Accept			json
+// @Produce		json
+// @Param			id					path		int						true	"playlistID"
+// @Param			entryID				path		int						true	"entryID"
+// @Param			MovePlaylistEntry	body		dto.MovePlaylistEntry	true	"New position"
+// @Success		200					{object}	map[string]any			"success response"
+// @Failure		500					{object}	map[string]string		"failure response"
+// @Failure		400					{object}	map[string]string		"failure response"
+// @Router			/playlists/{id}/entries/{entryID}/move [post]
+func (h *Handler) MovePlaylistEntry(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.MovePlaylistEntry"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		playlistID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || playlistID <= 0 {
+			h.log.Error("invalid playlist ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid playlist ID")
+			return
+		}
+
+		entryID, err := strconv.Atoi(chi.URLParam(r, "entryID"))
+		if err != nil || entryID <= 0 {
+			h.log.Error("invalid entry ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid entry ID")
+			return
+		}
+
+		var move dto.MovePlaylistEntry
+		if err := render.Decode(r, &move); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := move.Validate(); err != nil {
+			h.log.Error("validation error in move info", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		position, err := h.service.MovePlaylistEntry(ctx, playlistID, entryID, move, requestID)
+		if err != nil {
+			h.log.Error("failed to move playlist entry", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"playlist_id": playlistID,
+			"entry_id":    entryID,
+			"position":    position,
+			"detail":      "entry successfully moved",
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package library
+
+import (
+	"context"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Link song to original
+// @Description	Mark the song as a cover, remix, live or acoustic version of the original. Linking the same songs again changes the relation.
+// @Tags			Versions
+// @Accept			json
+// @Produce		json
+// @Param			id				path		int					true	"songID"
+// @Param			SongRelation	body		dto.SongRelation	true	"Relation"
+// @Success		200				{object}	map[string]any		"success response"
+// @Failure		500				{object}	map[string]string	"failure response"
+// @Failure		400				{object}	map[string]string	"failure response"
+// @Router			/song/{id}/relations [post]
+func (h *Handler) LinkSongs(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.LinkSongs"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		var relation dto.SongRelation
+		if err := render.Decode(r, &relation); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := relation.Validate(); err != nil {
+			h.log.Error("validation error in relation", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		if err := h.service.LinkSongs(ctx, songID, relation, requestID); err != nil {
+			h.log.Error("failed to link songs", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id":     songID,
+			"original_id": relation.OriginalID,
+			"relation":    relation.Relation,
+			"detail":      "songs successfully linked",
+		})
+	}
+}
+
+// @Summary		Unlink song from original
+// @Description	Remove relation between the song and the original.
+// @Tags			Versions
+// @Accept			json
+// @Produce		json
+// @Param			id			path		int					true	"songID"
+// @Param			originalID	path		int					true	"originalID"
+// @Success		200			{object}	map[string]any		"success response"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/song/{id}/relations/{originalID} [delete]
+func (h *Handler) UnlinkSongs(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.UnlinkSongs"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		originalID, err := strconv.Atoi(chi.URLParam(r, "originalID"))
+		if err != nil || originalID <= 0 {
+			h.log.Error("invalid original ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid original ID")
+			return
+		}
+
+		if err := h.service.UnlinkSongs(ctx, songID, originalID, requestID); err != nil {
+			h.log.Error("failed to unlink songs", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id":     songID,
+			"original_id": originalID,
+			"detail":      "songs successfully unlinked",
+		})
+	}
+}
+
+// @Summary		Get song versions
+// @Description	Get all songs connected to the song through cover, remix, live and acoustic relations, with the relations between them.
+// @Tags			Versions
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"songID"
+// @Success		200	{object}	models.SongVersions	"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/song/{id}/versions [get]
+func (h *Handler) GetSongVersions(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetSongVersions"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		versions, err := h.service.GetSongVersions(ctx, songID, requestID)
+		if err != nil {
+			h.log.Error("failed to get song versions", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, versions)
+	}
+}
@@ -0,0 +1,160 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/diff"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// @Summary		Get song revisions
+// @Description	Get revision history of the song, newest first. Every revision holds the changed fields with old and new values and the song state after the change.
+// @Tags			Revisions
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"songID"
+// @Success		200	{array}		models.SongRevision	"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/song/{id}/revisions [get]
+func (h *Handler) GetSongRevisions(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetSongRevisions"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		revisions, err := h.service.GetSongRevisions(ctx, songID, requestID)
+		if err != nil {
+			h.log.Error("failed to get song revisions", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, revisions)
+	}
+}
+
+// @Summary		Get song text diff
+// @Description	Get line-level diff of the song text between two revisions. Texts with more than 2000 changed lines are not compared.
+// @Tags			Revisions
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"songID"
+// @Param			from	query		int					true	"old revision"
+// @Param			to		query		int					true	"new revision"
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		422		{object}	map[string]string	"texts are too long to diff"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/song/{id}/revisions/diff [get]
+func (h *Handler) GetSongTextDiff(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetSongTextDiff"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		from, err := strconv.Atoi(r.URL.Query().Get("from"))
+		if err != nil || from <= 0 {
+			h.log.Error("invalid from revision", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid from revision")
+			return
+		}
+
+		to, err := strconv.Atoi(r.URL.Query().Get("to"))
+		if err != nil || to <= 0 {
+			h.log.Error("invalid to revision", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid to revision")
+			return
+		}
+
+		lines, err := h.service.GetSongTextDiff(ctx, songID, from, to, requestID)
+		if err != nil {
+			h.log.Error("failed to get song text diff", sl.Err(err))
+			if errors.Is(err, diff.ErrTooLarge) {
+				handlers.ErrorResponse(w, r, 422, err.Error())
+				return
+			}
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"from":    from,
+			"to":      to,
+			"lines":   lines,
+		})
+	}
+}
+
+// @Summary		Restore song revision
+// @Description	Restore every field of the song to the given revision, the restore is recorded as a new revision.
+// @Tags			Revisions
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"songID"
+// @Param			rev	path		int					true	"revision"
+// @Success		200	{object}	map[string]any		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/song/{id}/revisions/{rev}/restore [post]
+func (h *Handler) RestoreSongRevision(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.RestoreSongRevision"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		revision, err := strconv.Atoi(chi.URLParam(r, "rev"))
+		if err != nil || revision <= 0 {
+			h.log.Error("invalid revision", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid revision")
+			return
+		}
+
+		if err := h.service.RestoreSongRevision(ctx, songID, revision, requestID); err != nil {
+			h.log.Error("failed to restore song revision", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id":  songID,
+			"revision": revision,
+			"detail":   "song revision successfully restored",
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package library
+
+import (
+	"context"
+	"music-library/internal/domain/dto"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// @Summary		Add song tags
+// @Description	Add tags to the song, unknown tags are created.
+// @Tags			Tags
+// @Accept			json
+// @Produce		json
+// @Param			id			path		int					true	"songID"
+// @Param			SongTags	body		dto.SongTags		true	"Tags"
+// @Success		200			{object}	map[string]any		"success response"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/song/{id}/tags [post]
+func (h *Handler) AddSongTags(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.AddSongTags"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		var tags dto.SongTags
+		if err := render.Decode(r, &tags); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := tags.Validate(); err != nil {
+			h.log.Error("validation error in tags", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		if err := h.service.AddSongTags(ctx, songID, tags, requestID); err != nil {
+			h.log.Error("failed to add song tags", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"detail":  "tags successfully added",
+		})
+	}
+}
+
+// @Summary		Remove song tag
+// @Description	Remove tag from the song.
+// @Tags			Tags
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"songID"
+// @Param			tag	path		string				true	"tag"
+// @Success		200	{object}	map[string]any		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Router			/song/{id}/tags/{tag} [delete]
+func (h *Handler) RemoveSongTag(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.RemoveSongTag"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		tag, err := url.PathUnescape(chi.URLParam(r, "tag"))
+		if err != nil || strings.TrimSpace(tag) == "" {
+			h.log.Error("invalid tag")
+			handlers.ErrorResponse(w, r, 400, "invalid tag")
+			return
+		}
+
+		if err := h.service.RemoveSongTag(ctx, songID, dto.NormalizeName(tag), requestID); err != nil {
+			h.log.Error("failed to remove song tag", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"detail":  "tag successfully removed",
+		})
+	}
+}
+
+// @Summary		Add song genres
+// @Description	Add genres to the song, unknown genres are created.
+// @Tags			Tags
+// @Accept			json
+// @Produce		json
+// @Param			id			path		int					true	"songID"
+// @Param			SongGenres	body		dto.SongGenres		true	"Genres"
+// @Success		200			{object}	map[string]any		"success response"
+// @Failure		500			{object}	map[string]string	"failure response"
+// @Failure		400			{object}	map[string]string	"failure response"
+// @Router			/song/{id}/genres [post]
+func (h *Handler) AddSongGenres(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.AddSongGenres"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		var genres dto.SongGenres
+		if err := render.Decode(r, &genres); err != nil {
+			h.log.Error("failed to decode model", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "failed to decode model")
+			return
+		}
+		if err := genres.Validate(); err != nil {
+			h.log.Error("validation error in genres", sl.Err(err))
+			handlers.ErrorResponse(w, r, 422, err.Error())
+			return
+		}
+
+		if err := h.service.AddSongGenres(ctx, songID, genres, requestID); err != nil {
+			h.log.Error("failed to add song genres", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"detail":  "genres successfully added",
+		})
+	}
+}
+
+// @Summary		Remove song genre
+// @Description	Remove genre from the song.
+// @Tags			Tags
+// @Accept			json
+// @Produce		json
+// @Param			id		path		int					true	"songID"
+// @Param			genre	path		string				true	"genre"
+// @Success		200		{object}	map[string]any		"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/song/{id}/genres/{genre} [delete]
+func (h *Handler) RemoveSongGenre(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.RemoveSongGenre"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		genre, err := url.PathUnescape(chi.URLParam(r, "genre"))
+		if err != nil || strings.TrimSpace(genre) == "" {
+			h.log.Error("invalid genre")
+			handlers.ErrorResponse(w, r, 400, "invalid genre")
+			return
+		}
+
+		if err := h.service.RemoveSongGenre(ctx, songID, dto.NormalizeName(genre), requestID); err != nil {
+			h.log.Error("failed to remove song genre", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"detail":  "genre successfully removed",
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"music-library/internal/handlers"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/storage"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// @Summary		Get trash
+// @Description	Get deleted songs, most recently deleted first. Songs are purged once they are older than the configured retention.
+// @Tags			Trash
+// @Accept			json
+// @Produce		json
+// @Param			limit	query		int					true	"limit"		default(10)
+// @Param			offset	query		int					true	"offset"	default(0)
+// @Success		200		{array}		models.TrashItem	"success response"
+// @Failure		500		{object}	map[string]string	"failure response"
+// @Failure		400		{object}	map[string]string	"failure response"
+// @Router			/trash [get]
+func (h *Handler) GetTrash(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.GetTrash"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = 10
+		}
+
+		offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+		if err != nil || offset < 0 {
+			offset = 0
+		}
+
+		items, err := h.service.GetTrash(ctx, limit, offset, requestID)
+		if err != nil {
+			h.log.Error("failed to get trash", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, items)
+	}
+}
+
+// @Summary		Restore song
+// @Description	Restore deleted song from the trash.
+// @Tags			Trash
+// @Accept			json
+// @Produce		json
+// @Param			id	path		int					true	"songID"
+// @Success		200	{object}	map[string]any		"success response"
+// @Failure		500	{object}	map[string]string	"failure response"
+// @Failure		400	{object}	map[string]string	"failure response"
+// @Failure		409	{object}	map[string]any		"song is already in the library"
+// @Router			/song/{id}/restore [post]
+func (h *Handler) RestoreSong(ctx context.Context) http.HandlerFunc {
+	const op = "handlers.library.RestoreSong"
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetReqID(r.Context())
+
+		h.log = with.WithOpAndRequestID(h.log, op, requestID)
+
+		songID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || songID <= 0 {
+			h.log.Error("invalid song ID", sl.Err(err))
+			handlers.ErrorResponse(w, r, 400, "invalid song ID")
+			return
+		}
+
+		if err := h.service.RestoreSong(ctx, songID, requestID); err != nil {
+			h.log.Error("failed to restore song", sl.Err(err))
+			var dupErr *storage.DuplicateSongError
+			if errors.As(err, &dupErr) {
+				handlers.ErrorResponse(w, r, http.StatusConflict, map[string]any{
+					"message": dupErr.Error(),
+					"id":      dupErr.ID,
+				})
+				return
+			}
+			handlers.ErrorResponse(w, r, 400, err.Error())
+			return
+		}
+
+		handlers.SuccessResponse(w, r, 200, map[string]any{
+			"song_id": songID,
+			"detail":  "song was successfully restored",
+		})
+	}
+}
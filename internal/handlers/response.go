@@ -19,3 +19,8 @@ func SuccessResponse(w http.ResponseWriter, r *http.Request, status int, data in
 	render.Status(r, status)
 	render.JSON(w, r, data)
 }
+
+func TextResponse(w http.ResponseWriter, r *http.Request, status int, text string) {
+	render.Status(r, status)
+	render.PlainText(w, r, text)
+}
@@ -0,0 +1,153 @@
+// Package audiotag reads song metadata from ID3 tags, FLAC Vorbis comments
+// and MP4 atoms.
+package audiotag
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrUnsupported = errors.New("unsupported audio format")
+
+// maxTagSize bounds the tag data read into memory, embedded artwork makes tags
+// large but not this large.
+const maxTagSize = 64 << 20
+
+// Tags is the metadata of an audio file, empty fields were not tagged.
+// Duration is in seconds.
+type Tags struct {
+	Format   string
+	Artists  []string
+	Title    string
+	Album    string
+	Date     string
+	Track    int
+	Lyrics   string
+	ISRC     string
+	BPM      float64
+	Duration int
+}
+
+// Read detects the format by the content and reads its tags. An MP3 file
+// falls back to the ID3v1 tag for fields the ID3v2 tag does not have, a FLAC
+// file prefers its Vorbis comments over a leading ID3v2 tag.
+func Read(r io.ReadSeeker) (Tags, error) {
+	magic, err := peek(r, 8)
+	if err != nil {
+		return Tags{}, ErrUnsupported
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("ID3")):
+		tags, err := readID3v2(r)
+		if err != nil {
+			return Tags{}, err
+		}
+		if next, err := peek(r, 4); err == nil && string(next) == "fLaC" {
+			flac, err := readFLAC(r)
+			if err != nil {
+				return Tags{}, err
+			}
+			flac.fill(tags)
+			return flac, nil
+		}
+		if v1, ok, err := readID3v1(r); err == nil && ok {
+			tags.fill(v1)
+		}
+		return tags, nil
+	case string(magic[:4]) == "fLaC":
+		return readFLAC(r)
+	case string(magic[4:8]) == "ftyp":
+		return readMP4(r)
+	case magic[0] == 0xFF && magic[1]&0xE0 == 0xE0:
+		// an MPEG frame without an ID3v2 tag
+		tags, ok, err := readID3v1(r)
+		if err != nil {
+			return Tags{}, err
+		}
+		if !ok {
+			return Tags{Format: "mp3"}, nil
+		}
+		return tags, nil
+	}
+	return Tags{}, ErrUnsupported
+}
+
+// peek reads n bytes and seeks back.
+func peek(r io.ReadSeeker, n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(int64(-n), io.SeekCurrent); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// fill sets the fields t does not have from o.
+func (t *Tags) fill(o Tags) {
+	if len(t.Artists) == 0 {
+		t.Artists = o.Artists
+	}
+	if t.Title == "" {
+		t.Title = o.Title
+	}
+	if t.Album == "" {
+		t.Album = o.Album
+	}
+	if t.Date == "" {
+		t.Date = o.Date
+	}
+	if t.Track == 0 {
+		t.Track = o.Track
+	}
+	if t.Lyrics == "" {
+		t.Lyrics = o.Lyrics
+	}
+	if t.ISRC == "" {
+		t.ISRC = o.ISRC
+	}
+	if t.BPM == 0 {
+		t.BPM = o.BPM
+	}
+	if t.Duration == 0 {
+		t.Duration = o.Duration
+	}
+}
+
+// parseTrack reads track numbers like "3" and "3/12".
+func parseTrack(s string) int {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "/")
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+func parseBPM(s string) float64 {
+	bpm, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || bpm < 0 || math.IsInf(bpm, 0) || math.IsNaN(bpm) {
+		return 0
+	}
+	return bpm
+}
+
+func seconds(duration uint64, scale uint64) int {
+	if scale == 0 {
+		return 0
+	}
+	return int((duration + scale/2) / scale)
+}
+
+func appendValue(values []string, value string) []string {
+	if value = strings.TrimSpace(value); value != "" {
+		return append(values, value)
+	}
+	return values
+}
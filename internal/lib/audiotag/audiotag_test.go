@@ -0,0 +1,254 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func syncsafeSize(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+func id3Tag(major byte, flags byte, data []byte) []byte {
+	tag := append([]byte{'I', 'D', '3', major, 0, flags}, syncsafeSize(len(data))...)
+	return append(tag, data...)
+}
+
+func id3Frame(major byte, id string, flags uint16, body []byte) []byte {
+	frame := []byte(id)
+	if major == 4 {
+		frame = append(frame, syncsafeSize(len(body))...)
+	} else {
+		frame = binary.BigEndian.AppendUint32(frame, uint32(len(body)))
+	}
+	frame = binary.BigEndian.AppendUint16(frame, flags)
+	return append(frame, body...)
+}
+
+func latin1Text(s string) []byte {
+	return append([]byte{0}, s...)
+}
+
+// utf16Text encodes s as UTF-16 with a byte order mark.
+func utf16Text(s string, order binary.AppendByteOrder) []byte {
+	b := []byte{1}
+	b = order.AppendUint16(b, 0xFEFF)
+	for _, r := range s {
+		b = order.AppendUint16(b, uint16(r))
+	}
+	return b
+}
+
+func syncBytes(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte{0xFF}, []byte{0xFF, 0x00})
+}
+
+func TestReadID3v2(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want Tags
+	}{
+		{
+			name: "v2.3 text frames",
+			data: id3Tag(3, 0, bytes.Join([][]byte{
+				id3Frame(3, "TPE1", 0, latin1Text("Muse")),
+				id3Frame(3, "TIT2", 0, latin1Text("Starlight")),
+				id3Frame(3, "TALB", 0, latin1Text("Black Holes and Revelations")),
+				id3Frame(3, "TRCK", 0, latin1Text("2/11")),
+				id3Frame(3, "TYER", 0, latin1Text("2006")),
+				id3Frame(3, "TDAT", 0, latin1Text("0309")),
+			}, nil)),
+			want: Tags{
+				Format:  "id3v2.3",
+				Artists: []string{"Muse"},
+				Title:   "Starlight",
+				Album:   "Black Holes and Revelations",
+				Date:    "2006-09-03",
+				Track:   2,
+			},
+		},
+		{
+			name: "UTF-16 with either byte order mark",
+			data: id3Tag(3, 0, bytes.Join([][]byte{
+				id3Frame(3, "TPE1", 0, utf16Text("Сплин", binary.LittleEndian)),
+				id3Frame(3, "TIT2", 0, utf16Text("Выхода нет", binary.BigEndian)),
+			}, nil)),
+			want: Tags{Format: "id3v2.3", Artists: []string{"Сплин"}, Title: "Выхода нет"},
+		},
+		{
+			name: "v2.4 values separated by nulls",
+			data: id3Tag(4, 0, id3Frame(4, "TPE1", 0, latin1Text("Muse\x00Queen"))),
+			want: Tags{Format: "id3v2.4", Artists: []string{"Muse", "Queen"}},
+		},
+		{
+			name: "v2.3 unsynchronised tag",
+			data: id3Tag(3, 0x80, syncBytes(id3Frame(3, "TIT2", 0, latin1Text("a\xffb")))),
+			want: Tags{Format: "id3v2.3", Title: "aÿb"},
+		},
+		{
+			name: "v2.4 unsynchronised frame",
+			data: id3Tag(4, 0, id3Frame(4, "TIT2", 0x02, syncBytes(latin1Text("a\xffb")))),
+			want: Tags{Format: "id3v2.4", Title: "aÿb"},
+		},
+		{
+			name: "truncated frame ends the tag",
+			data: id3Tag(3, 0, bytes.Join([][]byte{
+				id3Frame(3, "TIT2", 0, latin1Text("Starlight")),
+				id3Frame(3, "TPE1", 0, latin1Text("Muse"))[:14],
+			}, nil)),
+			want: Tags{Format: "id3v2.3", Title: "Starlight"},
+		},
+		{
+			name: "frame larger than the tag is skipped",
+			data: id3Tag(3, 0, bytes.Join([][]byte{
+				id3Frame(3, "TIT2", 0, latin1Text("Starlight")),
+				{'T', 'P', 'E', '1', 0x7F, 0xFF, 0xFF, 0xFF, 0, 0, 0, 'M'},
+			}, nil)),
+			want: Tags{Format: "id3v2.3", Title: "Starlight"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadID3v2Errors(t *testing.T) {
+	frame := id3Frame(3, "TIT2", 0, latin1Text("Starlight"))
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "tag cut short",
+			data: id3Tag(3, 0, frame)[:15],
+			want: "malformed ID3v2 tag",
+		},
+		{
+			name: "tag over the size limit",
+			data: append([]byte{'I', 'D', '3', 3, 0, 0, 0x7F, 0x7F, 0x7F, 0x7F}, frame...),
+			want: "too large",
+		},
+		{
+			name: "unknown version",
+			data: id3Tag(5, 0, frame),
+			want: "unsupported ID3v2.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Read() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func mp4Atom(typ string, children ...[]byte) []byte {
+	body := bytes.Join(children, nil)
+	atom := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(atom, typ...), body...)
+}
+
+// mp4Atom64 writes the size in the 64 bit field after the type.
+func mp4Atom64(typ string, children ...[]byte) []byte {
+	body := bytes.Join(children, nil)
+	atom := binary.BigEndian.AppendUint32(nil, 1)
+	atom = append(atom, typ...)
+	atom = binary.BigEndian.AppendUint64(atom, uint64(16+len(body)))
+	return append(atom, body...)
+}
+
+func mp4Item(typ string, value []byte) []byte {
+	return mp4Atom(typ, mp4Atom("data", []byte{0, 0, 0, 1, 0, 0, 0, 0}, value))
+}
+
+func mp4Moov(atom func(string, ...[]byte) []byte) []byte {
+	// version 0 movie header, a timescale of 1000 and 4 minutes
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], 240000)
+
+	return atom("moov",
+		mp4Atom("mvhd", mvhd),
+		mp4Atom("udta", mp4Atom("meta", []byte{0, 0, 0, 0},
+			mp4Atom("hdlr", make([]byte, 25)),
+			mp4Atom("ilst",
+				mp4Item("\xa9ART", []byte("Muse")),
+				mp4Item("\xa9nam", []byte("Starlight")),
+				mp4Item("trkn", []byte{0, 0, 0, 2, 0, 11, 0, 0}),
+			),
+		)),
+	)
+}
+
+func TestReadMP4(t *testing.T) {
+	ftyp := mp4Atom("ftyp", []byte("M4A \x00\x00\x00\x00"))
+	want := Tags{Format: "mp4", Artists: []string{"Muse"}, Title: "Starlight", Track: 2, Duration: 240}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "32 bit sizes", data: bytes.Join([][]byte{ftyp, mp4Atom("free", make([]byte, 16)), mp4Moov(mp4Atom)}, nil)},
+		{name: "64 bit moov size", data: bytes.Join([][]byte{ftyp, mp4Moov(mp4Atom64)}, nil)},
+		{name: "64 bit size of a skipped atom", data: bytes.Join([][]byte{ftyp, mp4Atom64("mdat", make([]byte, 32)), mp4Moov(mp4Atom)}, nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Read() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestReadMP4Errors(t *testing.T) {
+	ftyp := mp4Atom("ftyp", []byte("M4A \x00\x00\x00\x00"))
+	huge := func(typ string, size uint64) []byte {
+		atom := append(binary.BigEndian.AppendUint32(nil, 1), typ...)
+		return binary.BigEndian.AppendUint64(atom, size)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "no moov", data: bytes.Join([][]byte{ftyp, mp4Atom("mdat", make([]byte, 8))}, nil)},
+		{name: "moov cut short", data: bytes.Join([][]byte{ftyp, mp4Moov(mp4Atom)[:40]}, nil)},
+		{name: "moov over the size limit", data: bytes.Join([][]byte{ftyp, huge("moov", 1<<40)}, nil)},
+		{name: "64 bit size past the end", data: bytes.Join([][]byte{ftyp, huge("mdat", 1<<62), mp4Moov(mp4Atom)}, nil)},
+		{name: "64 bit size over the int64 range", data: bytes.Join([][]byte{ftyp, huge("mdat", 1<<63+16), mp4Moov(mp4Atom)}, nil)},
+		{name: "64 bit size smaller than its header", data: bytes.Join([][]byte{ftyp, huge("mdat", 12)}, nil)},
+		{name: "64 bit size cut short", data: append(ftyp, 0, 0, 0, 1, 'm', 'd', 'a', 't', 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(bytes.NewReader(tt.data)); err == nil {
+				t.Error("Read() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package audiotag
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	flacStreamInfo    = 0
+	flacVorbisComment = 4
+)
+
+// readFLAC reads the stream info for the duration and the Vorbis comments,
+// the other metadata blocks and the audio are skipped.
+func readFLAC(r io.ReadSeeker) (Tags, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil || string(magic[:]) != "fLaC" {
+		return Tags{}, ErrUnsupported
+	}
+
+	tags := Tags{Format: "flac"}
+	for {
+		var h [4]byte
+		if _, err := io.ReadFull(r, h[:]); err != nil {
+			return Tags{}, fmt.Errorf("malformed FLAC metadata: %w", err)
+		}
+		last, typ := h[0]&0x80 != 0, h[0]&0x7F
+		size := int64(h[1])<<16 | int64(h[2])<<8 | int64(h[3])
+
+		switch typ {
+		case flacStreamInfo, flacVorbisComment:
+			block := make([]byte, size)
+			if _, err := io.ReadFull(r, block); err != nil {
+				return Tags{}, fmt.Errorf("malformed FLAC metadata: %w", err)
+			}
+			if typ == flacStreamInfo {
+				tags.Duration = streamDuration(block)
+			} else if err := readVorbisComment(block, &tags); err != nil {
+				return Tags{}, err
+			}
+		default:
+			if _, err := r.Seek(size, io.SeekCurrent); err != nil {
+				return Tags{}, err
+			}
+		}
+
+		if last {
+			return tags, nil
+		}
+	}
+}
+
+// streamDuration gets the duration out of the 20 bit sample rate and the 36
+// bit sample count of the stream info.
+func streamDuration(b []byte) int {
+	if len(b) < 18 {
+		return 0
+	}
+	rate := uint64(b[10])<<12 | uint64(b[11])<<4 | uint64(b[12])>>4
+	samples := uint64(b[13]&0x0F)<<32 | uint64(binary.BigEndian.Uint32(b[14:18]))
+	return seconds(samples, rate)
+}
+
+// readVorbisComment reads the little endian vendor string and the list of
+// NAME=value comments, names are case insensitive and may repeat.
+func readVorbisComment(b []byte, tags *Tags) error {
+	next := func() (string, bool) {
+		if len(b) < 4 {
+			return "", false
+		}
+		n := binary.LittleEndian.Uint32(b)
+		if uint64(n) > uint64(len(b)-4) {
+			return "", false
+		}
+		s := string(b[4 : 4+n])
+		b = b[4+n:]
+		return s, true
+	}
+
+	if _, ok := next(); !ok {
+		return fmt.Errorf("malformed Vorbis comment")
+	}
+	if len(b) < 4 {
+		return fmt.Errorf("malformed Vorbis comment")
+	}
+	count := binary.LittleEndian.Uint32(b)
+	b = b[4:]
+
+	var year string
+	for i := uint32(0); i < count; i++ {
+		comment, ok := next()
+		if !ok {
+			return fmt.Errorf("malformed Vorbis comment")
+		}
+		name, value, ok := strings.Cut(comment, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToUpper(name) {
+		case "ARTIST":
+			tags.Artists = appendValue(tags.Artists, value)
+		case "TITLE":
+			tags.Title = value
+		case "ALBUM":
+			tags.Album = value
+		case "DATE":
+			tags.Date = value
+		case "YEAR":
+			year = value
+		case "TRACKNUMBER":
+			tags.Track = parseTrack(value)
+		case "LYRICS", "UNSYNCEDLYRICS":
+			if tags.Lyrics == "" {
+				tags.Lyrics = value
+			}
+		case "ISRC":
+			tags.ISRC = value
+		case "BPM":
+			tags.BPM = parseBPM(value)
+		}
+	}
+
+	if tags.Date == "" {
+		tags.Date = year
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package audiotag
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// readID3v2 reads an ID3v2.2, 2.3 or 2.4 tag and leaves r right after it.
+func readID3v2(r io.ReadSeeker) (Tags, error) {
+	var h [10]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return Tags{}, fmt.Errorf("malformed ID3v2 header: %w", err)
+	}
+
+	major, flags := h[3], h[5]
+	if major < 2 || major > 4 {
+		return Tags{}, fmt.Errorf("unsupported ID3v2.%d tag", major)
+	}
+
+	size := syncsafe(h[6:10])
+	if size > maxTagSize {
+		return Tags{}, fmt.Errorf("ID3v2 tag of %d bytes is too large", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return Tags{}, fmt.Errorf("malformed ID3v2 tag: %w", err)
+	}
+	if major == 4 && flags&0x10 != 0 {
+		if _, err := r.Seek(10, io.SeekCurrent); err != nil {
+			return Tags{}, err
+		}
+	}
+
+	if flags&0x80 != 0 && major < 4 {
+		data = unsynchronise(data)
+	}
+	if flags&0x40 != 0 && major > 2 && len(data) >= 4 {
+		ext := int(binary.BigEndian.Uint32(data))
+		if major == 4 {
+			ext = syncsafe(data[:4])
+		} else {
+			// the v2.3 size does not count itself
+			ext += 4
+		}
+		if ext > len(data) {
+			return Tags{}, fmt.Errorf("malformed ID3v2 extended header")
+		}
+		data = data[ext:]
+	}
+
+	tags := Tags{Format: fmt.Sprintf("id3v2.%d", major)}
+	var year, dayMonth string
+	for {
+		id, body, rest, ok := nextFrame(data, major)
+		if !ok {
+			break
+		}
+		data = rest
+
+		body, ok = frameBody(body, major, id.flags)
+		if !ok || len(body) == 0 {
+			continue
+		}
+
+		switch id.name {
+		case "TPE1", "TP1":
+			for _, artist := range textValues(body) {
+				tags.Artists = appendValue(tags.Artists, artist)
+			}
+		case "TIT2", "TT2":
+			tags.Title = textValue(body)
+		case "TALB", "TAL":
+			tags.Album = textValue(body)
+		case "TDRC":
+			tags.Date = textValue(body)
+		case "TYER", "TYE":
+			year = textValue(body)
+		case "TDAT", "TDA":
+			dayMonth = textValue(body)
+		case "TRCK", "TRK":
+			tags.Track = parseTrack(textValue(body))
+		case "TSRC", "TRC":
+			tags.ISRC = textValue(body)
+		case "TBPM", "TBP":
+			tags.BPM = parseBPM(textValue(body))
+		case "TLEN", "TLE":
+			if ms, err := strconv.ParseUint(textValue(body), 10, 64); err == nil {
+				tags.Duration = seconds(ms, 1000)
+			}
+		case "USLT", "ULT":
+			if tags.Lyrics == "" {
+				tags.Lyrics = lyricsValue(body)
+			}
+		}
+	}
+
+	if tags.Date == "" && year != "" {
+		tags.Date = year
+		// TDAT is DDMM
+		if len(dayMonth) == 4 {
+			tags.Date = fmt.Sprintf("%s-%s-%s", year, dayMonth[2:], dayMonth[:2])
+		}
+	}
+
+	return tags, nil
+}
+
+// readID3v1 reads the tag in the last 128 bytes of the file.
+func readID3v1(r io.ReadSeeker) (Tags, bool, error) {
+	if _, err := r.Seek(-128, io.SeekEnd); err != nil {
+		return Tags{}, false, nil
+	}
+	var b [128]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return Tags{}, false, err
+	}
+	if string(b[:3]) != "TAG" {
+		return Tags{}, false, nil
+	}
+
+	field := func(f []byte) string {
+		if i := bytes.IndexByte(f, 0); i >= 0 {
+			f = f[:i]
+		}
+		return strings.TrimSpace(latin1(f))
+	}
+
+	tags := Tags{
+		Format:  "id3v1",
+		Artists: appendValue(nil, field(b[33:63])),
+		Title:   field(b[3:33]),
+		Album:   field(b[63:93]),
+		Date:    field(b[93:97]),
+	}
+	// ID3v1.1 keeps the track in the last byte of the comment
+	if b[125] == 0 && b[126] != 0 {
+		tags.Track = int(b[126])
+	}
+	return tags, true, nil
+}
+
+type frameID struct {
+	name  string
+	flags uint16
+}
+
+func nextFrame(data []byte, major byte) (frameID, []byte, []byte, bool) {
+	header := 10
+	if major == 2 {
+		header = 6
+	}
+	if len(data) < header || data[0] == 0 {
+		// padding
+		return frameID{}, nil, nil, false
+	}
+
+	var id frameID
+	var size int
+	switch major {
+	case 2:
+		id.name = string(data[:3])
+		size = int(data[3])<<16 | int(data[4])<<8 | int(data[5])
+	case 3:
+		id.name = string(data[:4])
+		size = int(binary.BigEndian.Uint32(data[4:8]))
+		id.flags = binary.BigEndian.Uint16(data[8:10])
+	default:
+		id.name = string(data[:4])
+		size = syncsafe(data[4:8])
+		id.flags = binary.BigEndian.Uint16(data[8:10])
+	}
+
+	if size < 0 || size > len(data)-header {
+		return frameID{}, nil, nil, false
+	}
+	return id, data[header : header+size], data[header+size:], true
+}
+
+// frameBody undoes the frame level grouping, unsynchronisation and
+// compression, encrypted frames are skipped.
+func frameBody(body []byte, major byte, flags uint16) ([]byte, bool) {
+	var grouping, encrypted, compressed, unsync, dataLength bool
+	switch major {
+	case 3:
+		compressed, encrypted, grouping = flags&0x80 != 0, flags&0x40 != 0, flags&0x20 != 0
+		dataLength = compressed
+	case 4:
+		grouping, compressed, encrypted = flags&0x40 != 0, flags&0x08 != 0, flags&0x04 != 0
+		unsync, dataLength = flags&0x02 != 0, flags&0x01 != 0
+	}
+	if encrypted {
+		return nil, false
+	}
+
+	if major == 3 && dataLength {
+		// the v2.3 decompressed size comes before the group byte
+		if len(body) < 4 {
+			return nil, false
+		}
+		body = body[4:]
+	}
+	if grouping {
+		if len(body) < 1 {
+			return nil, false
+		}
+		body = body[1:]
+	}
+	if major == 4 && dataLength {
+		if len(body) < 4 {
+			return nil, false
+		}
+		body = body[4:]
+	}
+	if unsync {
+		body = unsynchronise(body)
+	}
+	if compressed {
+		zr, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, false
+		}
+		defer zr.Close()
+		body, err = io.ReadAll(io.LimitReader(zr, maxTagSize))
+		if err != nil {
+			return nil, false
+		}
+	}
+	return body, true
+}
+
+// textValues decodes a text frame, ID3v2.4 separates values with a null.
+func textValues(body []byte) []string {
+	enc, data := body[0], body[1:]
+	var values []string
+	for len(data) > 0 {
+		var value []byte
+		value, data = cutTerminated(enc, data)
+		values = append(values, decodeText(enc, value))
+	}
+	return values
+}
+
+func textValue(body []byte) string {
+	for _, value := range textValues(body) {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// lyricsValue decodes an unsynchronised lyrics frame: encoding, language,
+// content descriptor and the text.
+func lyricsValue(body []byte) string {
+	if len(body) < 4 {
+		return ""
+	}
+	enc := body[0]
+	_, text := cutTerminated(enc, body[4:])
+	return strings.TrimSpace(decodeText(enc, text))
+}
+
+// cutTerminated splits data after the first null of the encoding, UTF-16 has
+// a two byte null on a character boundary.
+func cutTerminated(enc byte, data []byte) ([]byte, []byte) {
+	if enc == 1 || enc == 2 {
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return data[:i], data[i+2:]
+			}
+		}
+		return data, nil
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return data[:i], data[i+1:]
+	}
+	return data, nil
+}
+
+func decodeText(enc byte, b []byte) string {
+	switch enc {
+	case 0:
+		return latin1(b)
+	case 1, 2:
+		order := binary.ByteOrder(binary.BigEndian)
+		if enc == 1 {
+			// without a byte order mark little endian is the usual guess
+			order = binary.LittleEndian
+			if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+				order, b = binary.BigEndian, b[2:]
+			} else if len(b) >= 2 && b[0] == 0xFF && b[1] == 0xFE {
+				b = b[2:]
+			}
+		}
+		units := make([]uint16, len(b)/2)
+		for i := range units {
+			units[i] = order.Uint16(b[2*i:])
+		}
+		return string(utf16.Decode(units))
+	default:
+		return strings.ToValidUTF8(string(b), string(utf8.RuneError))
+	}
+}
+
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
+
+// unsynchronise drops the zero byte inserted after every 0xFF.
+func unsynchronise(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte{0xFF, 0x00}, []byte{0xFF})
+}
@@ -0,0 +1,197 @@
+package audiotag
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+type atom struct {
+	typ  string
+	body []byte
+}
+
+// readMP4 reads the moov atom into memory and takes the duration from the
+// movie header and the tags from the iTunes item list, mdat is skipped.
+func readMP4(r io.ReadSeeker) (Tags, error) {
+	for {
+		size, typ, header, err := atomHeader(r)
+		if err == io.EOF {
+			return Tags{}, fmt.Errorf("moov atom not found")
+		}
+		if err != nil {
+			return Tags{}, err
+		}
+
+		if typ == "moov" {
+			if size == 0 || size-header > maxTagSize {
+				return Tags{}, fmt.Errorf("moov atom of %d bytes is too large", size)
+			}
+			moov := make([]byte, size-header)
+			if _, err := io.ReadFull(r, moov); err != nil {
+				return Tags{}, fmt.Errorf("malformed moov atom: %w", err)
+			}
+			return readMoov(moov), nil
+		}
+
+		if size == 0 {
+			// the atom runs to the end of the file
+			return Tags{}, fmt.Errorf("moov atom not found")
+		}
+		if size-header > math.MaxInt64 {
+			// would wrap to a seek back and read the same atoms again
+			return Tags{}, fmt.Errorf("malformed MP4 atom %q", typ)
+		}
+		if _, err := r.Seek(int64(size-header), io.SeekCurrent); err != nil {
+			return Tags{}, err
+		}
+	}
+}
+
+// atomHeader reads the size and type of an atom, a size of 1 is followed by
+// a 64 bit size.
+func atomHeader(r io.Reader) (uint64, string, uint64, error) {
+	var h [8]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return 0, "", 0, fmt.Errorf("malformed MP4 atom: %w", err)
+		}
+		return 0, "", 0, err
+	}
+
+	size, typ, header := uint64(binary.BigEndian.Uint32(h[:4])), string(h[4:]), uint64(8)
+	if size == 1 {
+		if _, err := io.ReadFull(r, h[:]); err != nil {
+			return 0, "", 0, fmt.Errorf("malformed MP4 atom: %w", err)
+		}
+		size, header = binary.BigEndian.Uint64(h[:]), 16
+	}
+	if size != 0 && size < header {
+		return 0, "", 0, fmt.Errorf("malformed MP4 atom %q", typ)
+	}
+	return size, typ, header, nil
+}
+
+// atoms splits b into the atoms it contains, a malformed atom ends the list.
+func atoms(b []byte) []atom {
+	var result []atom
+	for len(b) >= 8 {
+		size, typ, header := uint64(binary.BigEndian.Uint32(b)), string(b[4:8]), uint64(8)
+		if size == 1 {
+			if len(b) < 16 {
+				break
+			}
+			size, header = binary.BigEndian.Uint64(b[8:16]), 16
+		}
+		if size == 0 {
+			size = uint64(len(b))
+		}
+		if size < header || size > uint64(len(b)) {
+			break
+		}
+		result = append(result, atom{typ: typ, body: b[header:size]})
+		b = b[size:]
+	}
+	return result
+}
+
+func readMoov(moov []byte) Tags {
+	tags := Tags{Format: "mp4"}
+	for _, a := range atoms(moov) {
+		switch a.typ {
+		case "mvhd":
+			tags.Duration = movieDuration(a.body)
+		case "udta":
+			for _, u := range atoms(a.body) {
+				if u.typ == "meta" {
+					readMeta(u.body, &tags)
+				}
+			}
+		case "meta":
+			readMeta(a.body, &tags)
+		}
+	}
+	return tags
+}
+
+func movieDuration(b []byte) int {
+	if len(b) < 20 {
+		return 0
+	}
+	if b[0] == 1 {
+		if len(b) < 32 {
+			return 0
+		}
+		return seconds(binary.BigEndian.Uint64(b[24:32]), uint64(binary.BigEndian.Uint32(b[20:24])))
+	}
+	return seconds(uint64(binary.BigEndian.Uint32(b[16:20])), uint64(binary.BigEndian.Uint32(b[12:16])))
+}
+
+// readMeta reads the item list, the ISO meta atom has a version and flags
+// before its children while the QuickTime one does not.
+func readMeta(b []byte, tags *Tags) {
+	if len(b) >= 8 && string(b[4:8]) != "hdlr" {
+		b = b[4:]
+	}
+	for _, m := range atoms(b) {
+		if m.typ != "ilst" {
+			continue
+		}
+		var albumArtist string
+		for _, item := range atoms(m.body) {
+			name, value := itemValue(item)
+			switch item.typ {
+			case "\xa9ART":
+				tags.Artists = appendValue(tags.Artists, string(value))
+			case "aART":
+				albumArtist = string(value)
+			case "\xa9nam":
+				tags.Title = strings.TrimSpace(string(value))
+			case "\xa9alb":
+				tags.Album = strings.TrimSpace(string(value))
+			case "\xa9day":
+				tags.Date = strings.TrimSpace(string(value))
+			case "\xa9lyr":
+				tags.Lyrics = strings.TrimSpace(string(value))
+			case "trkn":
+				// reserved, track and total as 16 bit numbers
+				if len(value) >= 4 {
+					tags.Track = int(binary.BigEndian.Uint16(value[2:4]))
+				}
+			case "tmpo":
+				if len(value) >= 2 {
+					tags.BPM = float64(binary.BigEndian.Uint16(value))
+				}
+			case "----":
+				if strings.EqualFold(name, "ISRC") {
+					tags.ISRC = strings.TrimSpace(string(value))
+				}
+			}
+		}
+		if len(tags.Artists) == 0 {
+			tags.Artists = appendValue(nil, albumArtist)
+		}
+	}
+}
+
+// itemValue returns the value of the first data atom of the item and, for a
+// freeform item, its name.
+func itemValue(item atom) (string, []byte) {
+	var name string
+	for _, a := range atoms(item.body) {
+		switch a.typ {
+		case "name":
+			if len(a.body) >= 4 {
+				name = string(a.body[4:])
+			}
+		case "data":
+			// version, type, locale and the value
+			if len(a.body) >= 8 {
+				return name, a.body[8:]
+			}
+		}
+	}
+	return name, nil
+}
@@ -0,0 +1,94 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	value := "Muse"
+	c := Cursor{Sort: "group,-id", Filters: "f1", Values: []*string{&value, nil}, Before: true}
+
+	got, err := NewSigner([]byte("key")).Decode(NewSigner([]byte("key")).Encode(c))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("Decode() = %+v, want %+v", got, c)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	signer := NewSigner([]byte("key"))
+	value := "Muse"
+	token := signer.Encode(Cursor{Sort: "group,-id", Values: []*string{&value, nil}})
+	payload, sig, _ := strings.Cut(token, ".")
+
+	// a payload that still decodes, with the signature of the original one
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"group,-id","v":["Queen",null]}`))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "wrong key", token: NewSigner([]byte("other key")).Encode(Cursor{Sort: "group,-id", Values: []*string{&value, nil}})},
+		{name: "tampered payload", token: forged + "." + sig},
+		{name: "tampered signature", token: payload + "." + flip(sig)},
+		{name: "signature of another cursor", token: payload + "." + strings.SplitN(signer.Encode(Cursor{Sort: "id"}), ".", 2)[1]},
+		{name: "missing signature", token: payload},
+		{name: "empty signature", token: payload + "."},
+		{name: "payload not base64", token: "!!!." + sig},
+		{name: "signature not base64", token: payload + ".!!!"},
+		{name: "signed payload not JSON", token: base64.RawURLEncoding.EncodeToString([]byte("nope")) + "." + base64.RawURLEncoding.EncodeToString(signer.sign([]byte("nope")))},
+		{name: "empty", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := signer.Decode(tt.token); !errors.Is(err, ErrInvalid) {
+				t.Errorf("Decode() error = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestDecodeFor(t *testing.T) {
+	signer := NewSigner([]byte("key"))
+	value := "Muse"
+	token := signer.Encode(Cursor{Sort: "group,-id", Filters: "f1", Values: []*string{&value, nil}})
+
+	tests := []struct {
+		name    string
+		sort    string
+		filters string
+		valid   bool
+	}{
+		{name: "same listing", sort: "group,-id", filters: "f1", valid: true},
+		{name: "other sort", sort: "song,id", filters: "f1"},
+		{name: "other filters", sort: "group,-id", filters: "f2"},
+		{name: "no filters", sort: "group,-id", filters: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := signer.DecodeFor(token, tt.sort, tt.filters)
+			if tt.valid && err != nil {
+				t.Errorf("DecodeFor() error = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalid) {
+				t.Errorf("DecodeFor() error = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+// flip changes the first character of a base64 string to another valid one.
+func flip(s string) string {
+	if s[0] == 'A' {
+		return "B" + s[1:]
+	}
+	return "A" + s[1:]
+}
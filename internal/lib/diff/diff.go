@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxLines bounds the changed lines of both texts a diff compares, its
+// table takes memory for every pair of them. The lines the texts start and
+// end with in common do not count.
+const MaxLines = 2000
+
+var ErrTooLarge = errors.New("texts are too long to diff")
+
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+type Line struct {
+	Op      string `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+}
+
+// Lines returns the line-level diff between two texts based on their longest
+// common subsequence, deletions go before insertions within a changed block.
+// Texts whose changed part is longer than MaxLines fail with ErrTooLarge.
+func Lines(oldText string, newText string) ([]Line, error) {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	// the common prefix and suffix are equal lines without a table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA) > MaxLines || len(midB) > MaxLines {
+		return nil, ErrTooLarge
+	}
+
+	lines := make([]Line, 0, max(len(a), len(b)))
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, Line{Op: OpEqual, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+	}
+	lines = appendChanged(lines, midA, midB, prefix)
+	for k := suffix; k > 0; k-- {
+		lines = append(lines, Line{Op: OpEqual, Text: a[len(a)-k], OldLine: len(a) - k + 1, NewLine: len(b) - k + 1})
+	}
+
+	return lines, nil
+}
+
+// appendChanged appends the diff of the changed lines, offset is the number
+// of lines before them.
+func appendChanged(lines []Line, a []string, b []string, offset int) []Line {
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, Line{Op: OpEqual, Text: a[i], OldLine: offset + i + 1, NewLine: offset + j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, Line{Op: OpDelete, Text: a[i], OldLine: offset + i + 1})
+			i++
+		default:
+			lines = append(lines, Line{Op: OpInsert, Text: b[j], NewLine: offset + j + 1})
+			j++
+		}
+	}
+
+	return lines
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,112 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// script writes the diff as one line per entry: the op sign, the old and new
+// line numbers and the text.
+func script(lines []Line) []string {
+	signs := map[string]string{OpEqual: " ", OpDelete: "-", OpInsert: "+"}
+	var result []string
+	for _, line := range lines {
+		result = append(result, fmt.Sprintf("%s%d,%d %s", signs[line.Op], line.OldLine, line.NewLine, line.Text))
+	}
+	return result
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []string
+	}{
+		{
+			name: "equal texts",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			want: []string{" 1,1 a", " 2,2 b"},
+		},
+		{
+			name: "changed line in the middle",
+			old:  "a\nb\nc",
+			new:  "a\nx\nc",
+			want: []string{" 1,1 a", "-2,0 b", "+0,2 x", " 3,3 c"},
+		},
+		{
+			name: "inserted and deleted lines",
+			old:  "a\nb\nc\nd",
+			new:  "b\nc\nx\nd",
+			want: []string{"-1,0 a", " 2,1 b", " 3,2 c", "+0,3 x", " 4,4 d"},
+		},
+		{
+			name: "from empty",
+			old:  "",
+			new:  "a\nb",
+			want: []string{"+0,1 a", "+0,2 b"},
+		},
+		{
+			name: "to empty",
+			old:  "a",
+			new:  "",
+			want: []string{"-1,0 a"},
+		},
+		{
+			name: "windows line endings",
+			old:  "a\r\nb",
+			new:  "a\nb",
+			want: []string{" 1,1 a", " 2,2 b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := Lines(tt.old, tt.new)
+			if err != nil {
+				t.Fatalf("Lines() error = %v", err)
+			}
+			if got := script(lines); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinesLimit(t *testing.T) {
+	numbered := func(prefix string, n int) string {
+		lines := make([]string, n)
+		for i := range lines {
+			lines[i] = fmt.Sprintf("%s%d", prefix, i)
+		}
+		return strings.Join(lines, "\n")
+	}
+	common := numbered("same", 3*MaxLines)
+
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		tooLong bool
+	}{
+		{name: "changed lines at the limit", old: numbered("a", MaxLines), new: numbered("b", MaxLines)},
+		{name: "changed lines over the limit", old: numbered("a", MaxLines+1), new: "b", tooLong: true},
+		{name: "common lines do not count", old: common + "\na\n" + common, new: common + "\nb\n" + common},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Lines(tt.old, tt.new)
+			if tt.tooLong != errors.Is(err, ErrTooLarge) {
+				t.Errorf("Lines() error = %v, want too long %v", err, tt.tooLong)
+			}
+			if !tt.tooLong && err != nil {
+				t.Errorf("Lines() error = %v", err)
+			}
+		})
+	}
+}
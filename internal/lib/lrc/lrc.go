@@ -0,0 +1,274 @@
+package lrc
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type Word struct {
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Text    string `json:"text"`
+}
+
+// Line is a timed lyrics line, the end of the last line is unknown until the
+// song duration is taken into account.
+type Line struct {
+	StartMs int64  `json:"start_ms"`
+	EndMs   *int64 `json:"end_ms"`
+	Text    string `json:"text"`
+	Words   []Word `json:"words,omitempty"`
+}
+
+type Lyrics struct {
+	Tags  map[string]string `json:"tags"`
+	Lines []Line            `json:"lines"`
+}
+
+// ParseError points to the line of the source that could not be parsed.
+type ParseError struct {
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("lrc line %d: %s", e.Line, e.Msg)
+}
+
+var (
+	timeTagRe = regexp.MustCompile(`^\[(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?\]`)
+	metaTagRe = regexp.MustCompile(`^\[([A-Za-z#]+):(.*)\]$`)
+	wordTagRe = regexp.MustCompile(`<(\d{1,3}):(\d{1,2})(?:[.:](\d{1,3}))?>`)
+)
+
+// Parse reads LRC lyrics with optional enhanced word timestamps. Lines with
+// one time tag have to go forward through the file, a line with several time
+// tags is repeated at every one of them and put in time order among the
+// others. The offset tag is applied to all times.
+func Parse(src string) (Lyrics, error) {
+	lyrics := Lyrics{Tags: map[string]string{}}
+
+	var offset int64
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	num := 0
+	var last int64 = -1
+	for scanner.Scan() {
+		num++
+		raw := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\uFEFF"))
+		if raw == "" {
+			continue
+		}
+
+		if !timeTagRe.MatchString(raw) {
+			m := metaTagRe.FindStringSubmatch(raw)
+			if m == nil {
+				return Lyrics{}, &ParseError{Line: num, Msg: "expected a [mm:ss.xx] time tag or a [key:value] tag"}
+			}
+			key, val := strings.ToLower(m[1]), strings.TrimSpace(m[2])
+			if key == "offset" {
+				ms, err := strconv.ParseInt(strings.TrimPrefix(val, "+"), 10, 64)
+				if err != nil {
+					return Lyrics{}, &ParseError{Line: num, Msg: fmt.Sprintf("invalid offset %q", val)}
+				}
+				offset = ms
+			}
+			lyrics.Tags[key] = val
+			continue
+		}
+
+		var starts []int64
+		for {
+			m := timeTagRe.FindStringSubmatch(raw)
+			if m == nil {
+				break
+			}
+			ms, err := tagMs(m)
+			if err != nil {
+				return Lyrics{}, &ParseError{Line: num, Msg: err.Error()}
+			}
+			starts = append(starts, ms)
+			raw = raw[len(m[0]):]
+		}
+
+		text, words, err := parseWords(raw)
+		if err != nil {
+			return Lyrics{}, &ParseError{Line: num, Msg: err.Error()}
+		}
+
+		if len(starts) == 1 {
+			if starts[0] < last {
+				return Lyrics{}, &ParseError{Line: num, Msg: fmt.Sprintf("timestamp %s goes before the previous one %s", formatMs(starts[0]), formatMs(last))}
+			}
+			last = starts[0]
+		}
+		for _, start := range starts {
+			if len(words) > 0 && words[0].StartMs < start {
+				return Lyrics{}, &ParseError{Line: num, Msg: fmt.Sprintf("word timestamp %s goes before the line timestamp %s", formatMs(words[0].StartMs), formatMs(start))}
+			}
+			lyrics.Lines = append(lyrics.Lines, Line{StartMs: start, Text: text, Words: slices.Clone(words)})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Lyrics{}, &ParseError{Line: num + 1, Msg: err.Error()}
+	}
+
+	if len(lyrics.Lines) == 0 {
+		return Lyrics{}, &ParseError{Line: num, Msg: "no timed lines"}
+	}
+
+	slices.SortStableFunc(lyrics.Lines, func(a, b Line) int {
+		return cmp.Compare(a.StartMs, b.StartMs)
+	})
+
+	for i := range lyrics.Lines {
+		line := &lyrics.Lines[i]
+		line.StartMs = max(line.StartMs-offset, 0)
+		for j := range line.Words {
+			line.Words[j].StartMs = max(line.Words[j].StartMs-offset, 0)
+			if line.Words[j].EndMs != 0 {
+				line.Words[j].EndMs = max(line.Words[j].EndMs-offset, 0)
+			}
+		}
+	}
+	for i := range lyrics.Lines {
+		if i+1 < len(lyrics.Lines) {
+			end := lyrics.Lines[i+1].StartMs
+			lyrics.Lines[i].EndMs = &end
+		}
+		setWordEnds(&lyrics.Lines[i])
+	}
+
+	return lyrics, nil
+}
+
+// Format writes the lines back as LRC, with word timestamps when the lines
+// have them.
+func Format(lines []Line) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString("[" + formatMs(line.StartMs) + "]")
+		if len(line.Words) == 0 {
+			b.WriteString(line.Text)
+		} else {
+			for i, word := range line.Words {
+				if i > 0 {
+					b.WriteString(" ")
+				}
+				b.WriteString("<" + formatMs(word.StartMs) + ">" + word.Text)
+			}
+			// the end of the last word is kept when it is not the end of the line
+			if last := line.Words[len(line.Words)-1]; last.EndMs != 0 && (line.EndMs == nil || last.EndMs != *line.EndMs) {
+				b.WriteString("<" + formatMs(last.EndMs) + ">")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// SetLastEnd closes the last line and its last word at the given time, used
+// once the song duration is known.
+func SetLastEnd(lines []Line, endMs int64) {
+	if len(lines) == 0 {
+		return
+	}
+	last := &lines[len(lines)-1]
+	if last.EndMs != nil || endMs < last.StartMs {
+		return
+	}
+	last.EndMs = &endMs
+	setWordEnds(last)
+}
+
+// parseWords splits an enhanced LRC line into timed words, a line without
+// word tags is returned as plain text.
+func parseWords(raw string) (string, []Word, error) {
+	tags := wordTagRe.FindAllStringSubmatchIndex(raw, -1)
+	if len(tags) == 0 {
+		return strings.TrimSpace(raw), nil, nil
+	}
+	if strings.TrimSpace(raw[:tags[0][0]]) != "" {
+		return "", nil, fmt.Errorf("text before the first word timestamp")
+	}
+
+	words := make([]Word, 0, len(tags))
+	texts := make([]string, 0, len(tags))
+	for i, loc := range tags {
+		m := make([]string, 4)
+		for k := 0; k < 4; k++ {
+			if loc[2*k] >= 0 {
+				m[k] = raw[loc[2*k]:loc[2*k+1]]
+			}
+		}
+		ms, err := tagMs(m)
+		if err != nil {
+			return "", nil, err
+		}
+		if i > 0 && ms < words[i-1].StartMs {
+			return "", nil, fmt.Errorf("word timestamp %s goes before the previous one %s", formatMs(ms), formatMs(words[i-1].StartMs))
+		}
+
+		end := len(raw)
+		if i+1 < len(tags) {
+			end = tags[i+1][0]
+		}
+		text := strings.TrimSpace(raw[loc[1]:end])
+		words = append(words, Word{StartMs: ms, Text: text})
+		if text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	// a trailing tag without text only marks the end of the last word
+	if n := len(words); n > 1 && words[n-1].Text == "" {
+		words[n-2].EndMs = words[n-1].StartMs
+		words = words[:n-1]
+	}
+
+	return strings.Join(texts, " "), words, nil
+}
+
+func setWordEnds(line *Line) {
+	for i := range line.Words {
+		switch {
+		case i+1 < len(line.Words):
+			line.Words[i].EndMs = line.Words[i+1].StartMs
+		case line.Words[i].EndMs == 0 && line.EndMs != nil:
+			line.Words[i].EndMs = *line.EndMs
+		}
+	}
+}
+
+func tagMs(m []string) (int64, error) {
+	minutes, _ := strconv.ParseInt(m[1], 10, 64)
+	seconds, _ := strconv.ParseInt(m[2], 10, 64)
+	if seconds >= 60 {
+		return 0, fmt.Errorf("invalid seconds in timestamp %q", m[0])
+	}
+
+	var ms int64
+	if m[3] != "" {
+		frac, _ := strconv.ParseInt(m[3], 10, 64)
+		switch len(m[3]) {
+		case 1:
+			ms = frac * 100
+		case 2:
+			ms = frac * 10
+		default:
+			ms = frac
+		}
+	}
+	return (minutes*60+seconds)*1000 + ms, nil
+}
+
+func formatMs(ms int64) string {
+	return fmt.Sprintf("%02d:%02d.%02d", ms/60000, ms/1000%60, ms%1000/10)
+}
@@ -0,0 +1,117 @@
+package lrc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		starts []int64
+		texts  []string
+		words  [][]int64
+	}{
+		{
+			name:   "plain lines",
+			src:    "[ti:Song]\n[00:01.00]One\n[00:02.50]Two\n",
+			starts: []int64{1000, 2500},
+			texts:  []string{"One", "Two"},
+		},
+		{
+			name:   "compressed lines are put in time order",
+			src:    "[00:10.00][00:50.00]Chorus\n[00:20.00]Verse\n[00:30.00]Bridge\n",
+			starts: []int64{10000, 20000, 30000, 50000},
+			texts:  []string{"Chorus", "Verse", "Bridge", "Chorus"},
+		},
+		{
+			name:   "equal times keep the source order",
+			src:    "[00:05.00][00:01.00]A\n[00:05.00]B\n",
+			starts: []int64{1000, 5000, 5000},
+			texts:  []string{"A", "A", "B"},
+		},
+		{
+			name:   "offset is applied once to repeated words",
+			src:    "[offset:+1000]\n[00:01.00][00:02.00]<00:03.00>a <00:04.00>b\n",
+			starts: []int64{0, 1000},
+			texts:  []string{"a b", "a b"},
+			words:  [][]int64{{2000, 3000}, {2000, 3000}},
+		},
+		{
+			name:   "trailing word tag ends the last word",
+			src:    "[00:01.00]<00:01.00>a <00:02.00>b <00:03.00>\n",
+			starts: []int64{1000},
+			texts:  []string{"a b"},
+			words:  [][]int64{{1000, 2000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lyrics, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			var starts []int64
+			var texts []string
+			for _, line := range lyrics.Lines {
+				starts = append(starts, line.StartMs)
+				texts = append(texts, line.Text)
+			}
+			if !reflect.DeepEqual(starts, tt.starts) {
+				t.Errorf("starts = %v, want %v", starts, tt.starts)
+			}
+			if !reflect.DeepEqual(texts, tt.texts) {
+				t.Errorf("texts = %v, want %v", texts, tt.texts)
+			}
+
+			for i, want := range tt.words {
+				var got []int64
+				for _, word := range lyrics.Lines[i].Words {
+					got = append(got, word.StartMs)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("line %d word starts = %v, want %v", i, got, want)
+				}
+			}
+
+			for i := 0; i+1 < len(lyrics.Lines); i++ {
+				if end := lyrics.Lines[i].EndMs; end == nil || *end != lyrics.Lines[i+1].StartMs {
+					t.Errorf("line %d end = %v, want %d", i, end, lyrics.Lines[i+1].StartMs)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		line int
+	}{
+		{name: "single tag lines going back", src: "[00:10.00]A\n[00:05.00]B\n", line: 2},
+		{name: "words going back", src: "[00:01.00]<00:02.00>a <00:01.50>b\n", line: 1},
+		{name: "words before a repeated start", src: "[00:01.00][00:05.00]<00:02.00>a\n", line: 1},
+		{name: "text without a tag", src: "[00:01.00]A\nno tag\n", line: 2},
+		{name: "invalid seconds", src: "[00:61.00]A\n", line: 1},
+		{name: "invalid offset", src: "[offset:soon]\n[00:01.00]A\n", line: 1},
+		{name: "no timed lines", src: "[ti:Song]\n", line: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse() error = %v, want a ParseError", err)
+			}
+			if parseErr.Line != tt.line {
+				t.Errorf("error line = %d, want %d (%s)", parseErr.Line, tt.line, parseErr.Msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package partialdate
+
+import (
+	"fmt"
+	"time"
+)
+
+type Precision string
+
+const (
+	Year  Precision = "year"
+	Month Precision = "month"
+	Day   Precision = "day"
+)
+
+// Date is a date known up to its precision, Time is the first day of the
+// period.
+type Date struct {
+	Time      time.Time
+	Precision Precision
+}
+
+var layouts = []struct {
+	layout    string
+	precision Precision
+}{
+	{"2006-01-02", Day},
+	{"2006-01", Month},
+	{"2006", Year},
+	{"02.01.2006", Day},
+	{"01.2006", Month},
+}
+
+// Parse reads an ISO 8601 date (2006, 2006-07, 2006-07-16) or a dotted one
+// (07.2006, 16.07.2006).
+func Parse(s string) (Date, error) {
+	for _, l := range layouts {
+		if len(s) != len(l.layout) {
+			continue
+		}
+		if t, err := time.Parse(l.layout, s); err == nil {
+			return Date{Time: t, Precision: l.precision}, nil
+		}
+	}
+	return Date{}, fmt.Errorf("invalid date %q", s)
+}
+
+// End returns the last day of the period.
+func (d Date) End() time.Time {
+	switch d.Precision {
+	case Year:
+		return d.Time.AddDate(1, 0, -1)
+	case Month:
+		return d.Time.AddDate(0, 1, -1)
+	default:
+		return d.Time
+	}
+}
+
+// String renders the date in the dotted format up to its precision.
+func (d Date) String() string {
+	switch d.Precision {
+	case Year:
+		return d.Time.Format("2006")
+	case Month:
+		return d.Time.Format("01.2006")
+	default:
+		return d.Time.Format("02.01.2006")
+	}
+}
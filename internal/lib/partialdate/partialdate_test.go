@@ -0,0 +1,53 @@
+package partialdate
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		precision Precision
+		start     string
+		end       string
+		str       string
+	}{
+		{name: "year", s: "2006", precision: Year, start: "2006-01-01", end: "2006-12-31", str: "2006"},
+		{name: "month", s: "2006-07", precision: Month, start: "2006-07-01", end: "2006-07-31", str: "07.2006"},
+		{name: "short month", s: "2006-02", precision: Month, start: "2006-02-01", end: "2006-02-28", str: "02.2006"},
+		{name: "month of a leap year", s: "2004-02", precision: Month, start: "2004-02-01", end: "2004-02-29", str: "02.2004"},
+		{name: "day", s: "2006-07-16", precision: Day, start: "2006-07-16", end: "2006-07-16", str: "16.07.2006"},
+		{name: "dotted month", s: "12.2006", precision: Month, start: "2006-12-01", end: "2006-12-31", str: "12.2006"},
+		{name: "dotted day", s: "16.07.2006", precision: Day, start: "2006-07-16", end: "2006-07-16", str: "16.07.2006"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Parse(tt.s)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.s, err)
+			}
+			if d.Precision != tt.precision {
+				t.Errorf("Parse(%q) precision = %s, want %s", tt.s, d.Precision, tt.precision)
+			}
+			if got := d.Time.Format("2006-01-02"); got != tt.start {
+				t.Errorf("Parse(%q) = %s, want %s", tt.s, got, tt.start)
+			}
+			if got := d.End().Format("2006-01-02"); got != tt.end {
+				t.Errorf("End() = %s, want %s", got, tt.end)
+			}
+			if got := d.String(); got != tt.str {
+				t.Errorf("String() = %s, want %s", got, tt.str)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{"", "06", "2006-13", "2006-02-30", "2006/07", "7.2006", "2006-07-16T00:00"} {
+		t.Run(s, func(t *testing.T) {
+			if _, err := Parse(s); err == nil {
+				t.Errorf("Parse(%q) error = nil, want an error", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package querylang parses the search query language of the library:
+//
+//	group:muse OR group:radiohead -song:live year:2000..2010
+//
+// Terms next to each other are combined with AND. As in web search OR binds
+// stronger than AND, so the query above asks for songs of either group, a
+// leading - or NOT negates a term and parentheses group terms. A value is
+// a word or a quoted string, ranges are written as from..to where either end
+// may be left open. A term without a field is matched against any name.
+package querylang
+
+import (
+	"fmt"
+	"music-library/internal/lib/partialdate"
+)
+
+// Kind tells how the values of a field are read.
+type Kind int
+
+const (
+	// Text values are matched as a part of the field.
+	Text Kind = iota
+	// Exact values have to equal the field.
+	Exact
+	// Integer and Number values are a number or a range of numbers.
+	Integer
+	Number
+	// Date values are a partial date or a range of them.
+	Date
+)
+
+// Fields maps the field names a query may use to their kinds.
+type Fields map[string]Kind
+
+type Node interface {
+	node()
+}
+
+type And struct {
+	Nodes []Node
+}
+
+type Or struct {
+	Nodes []Node
+}
+
+type Not struct {
+	Node Node
+}
+
+// Match is a single term. Value holds Text and Exact values, Min and Max the
+// inclusive bounds of numbers and From and To the bounds of dates, nil for an
+// open end. Pos points to the value.
+type Match struct {
+	Pos   int
+	Field string
+	Kind  Kind
+	Value string
+	Min   *float64
+	Max   *float64
+	From  *partialdate.Date
+	To    *partialdate.Date
+}
+
+func (And) node()    {}
+func (Or) node()     {}
+func (Not) node()    {}
+func (*Match) node() {}
+
+// SyntaxError points to the character of the query, counted from 1, that
+// could not be parsed.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query position %d: %s", e.Pos, e.Msg)
+}
+
+// Walk calls fn for every match of the query in order and stops at the
+// first error.
+func Walk(node Node, fn func(m *Match) error) error {
+	switch n := node.(type) {
+	case And:
+		for _, child := range n.Nodes {
+			if err := Walk(child, fn); err != nil {
+				return err
+			}
+		}
+	case Or:
+		for _, child := range n.Nodes {
+			if err := Walk(child, fn); err != nil {
+				return err
+			}
+		}
+	case Not:
+		return Walk(n.Node, fn)
+	case *Match:
+		return fn(n)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package querylang
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenWord
+	tokenString
+	tokenColon
+	tokenMinus
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	typ tokenType
+	val string
+	pos int
+}
+
+// lex splits the query into tokens, positions count characters from 1. A
+// minus negates only at the start of a term, inside a word it is kept.
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+
+	termStart := true
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			termStart = true
+			continue
+		case c == '(':
+			tokens = append(tokens, token{typ: tokenLParen, val: "(", pos: pos})
+			i++
+			termStart = true
+			continue
+		case c == ')':
+			tokens = append(tokens, token{typ: tokenRParen, val: ")", pos: pos})
+			i++
+			termStart = true
+			continue
+		case c == ':':
+			tokens = append(tokens, token{typ: tokenColon, val: ":", pos: pos})
+			i++
+			termStart = false
+			continue
+		case c == '-' && termStart:
+			tokens = append(tokens, token{typ: tokenMinus, val: "-", pos: pos})
+			i++
+			continue
+		case c == '"':
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, &SyntaxError{Pos: pos, Msg: "unterminated quoted string"}
+				}
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenString, val: sb.String(), pos: pos})
+			termStart = false
+			continue
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`():"`, runes[i]) {
+			i++
+		}
+		tokens = append(tokens, token{typ: tokenWord, val: string(runes[start:i]), pos: pos})
+		termStart = false
+	}
+
+	tokens = append(tokens, token{typ: tokenEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
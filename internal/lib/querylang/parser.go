@@ -0,0 +1,322 @@
+package querylang
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"music-library/internal/lib/partialdate"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxLength, maxDepth and maxTerms bound the SQL a query compiles to.
+const (
+	MaxLength = 1000
+	maxDepth  = 32
+	maxTerms  = 64
+)
+
+type parser struct {
+	tokens []token
+	i      int
+	fields Fields
+	depth  int
+	terms  int
+}
+
+// Parse reads the query, fields are the fields it may use. Field names and
+// the AND, OR and NOT keywords are case sensitive, a keyword can be searched
+// for in quotes.
+func Parse(input string, fields Fields) (Node, error) {
+	if utf8.RuneCountInString(input) > MaxLength {
+		return nil, &SyntaxError{Pos: MaxLength + 1, Msg: fmt.Sprintf("query is longer than %d characters", MaxLength)}
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	if p.peek().typ == tokenEOF {
+		return nil, &SyntaxError{Pos: 1, Msg: "query is empty"}
+	}
+
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.typ != tokenEOF {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.val)}
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.typ != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.typ == tokenWord && t.val == keyword
+}
+
+// atTermEnd reports whether no term can start at the next token.
+func (p *parser) atTermEnd() bool {
+	t := p.peek()
+	return t.typ == tokenEOF || t.typ == tokenRParen || p.isKeyword("OR") || p.isKeyword("AND")
+}
+
+// expectTerm fails unless a term starts at the next token, after says what
+// came before.
+func (p *parser) expectTerm(after string) error {
+	if !p.atTermEnd() {
+		return nil
+	}
+	return &SyntaxError{Pos: p.peek().pos, Msg: fmt.Sprintf("expected a term after %s", after)}
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, &SyntaxError{Pos: p.peek().pos, Msg: "query is nested too deeply"}
+	}
+
+	var nodes []Node
+	for {
+		t := p.peek()
+		if t.typ == tokenEOF || t.typ == tokenRParen {
+			break
+		}
+
+		if p.isKeyword("AND") {
+			if len(nodes) == 0 {
+				return nil, &SyntaxError{Pos: t.pos, Msg: "expected a term before AND"}
+			}
+			p.next()
+			if err := p.expectTerm("AND"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 0 {
+		t := p.peek()
+		if t.typ == tokenEOF {
+			return nil, &SyntaxError{Pos: t.pos, Msg: "expected a term"}
+		}
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("expected a term before %s", t.val)}
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return And{Nodes: nodes}, nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	if p.isKeyword("OR") {
+		return nil, &SyntaxError{Pos: p.peek().pos, Msg: "expected a term before OR"}
+	}
+
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{left}
+	for p.isKeyword("OR") {
+		p.next()
+		if err := p.expectTerm("OR"); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return Or{Nodes: nodes}, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	t := p.peek()
+	if t.typ != tokenMinus && !p.isKeyword("NOT") {
+		return p.parsePrimary()
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, &SyntaxError{Pos: t.pos, Msg: "query is nested too deeply"}
+	}
+
+	p.next()
+	if err := p.expectTerm(t.val); err != nil {
+		return nil, err
+	}
+	node, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return Not{Node: node}, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+
+	switch t.typ {
+	case tokenLParen:
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().typ != tokenRParen {
+			return nil, &SyntaxError{Pos: t.pos, Msg: "unclosed parenthesis"}
+		}
+		p.next()
+		return node, nil
+	case tokenWord, tokenString:
+		if t.typ == tokenWord && p.peek().typ == tokenColon {
+			colon := p.next()
+			field := strings.ToLower(t.val)
+			kind, ok := p.fields[field]
+			if !ok {
+				return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unknown field %q", t.val)}
+			}
+			v := p.next()
+			if v.typ != tokenWord && v.typ != tokenString {
+				return nil, &SyntaxError{Pos: colon.pos + 1, Msg: fmt.Sprintf("expected a value after %s:", t.val)}
+			}
+			return p.match(field, kind, v)
+		}
+		return p.match("", Text, t)
+	}
+
+	return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.val)}
+}
+
+func (p *parser) match(field string, kind Kind, t token) (*Match, error) {
+	p.terms++
+	if p.terms > maxTerms {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("query has more than %d terms", maxTerms)}
+	}
+
+	m := &Match{Pos: t.pos, Field: field, Kind: kind, Value: strings.TrimSpace(t.val)}
+	if m.Value == "" {
+		return nil, &SyntaxError{Pos: t.pos, Msg: "empty value"}
+	}
+
+	var err error
+	switch kind {
+	case Integer, Number:
+		m.Min, m.Max, err = numberRange(m.Value, kind == Integer)
+		if err != nil {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("invalid %s value %q: %s", field, m.Value, err)}
+		}
+	case Date:
+		m.From, m.To, err = dateRange(m.Value)
+		if err != nil {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("invalid %s value %q: %s", field, m.Value, err)}
+		}
+	}
+	return m, nil
+}
+
+// numberRange reads a number or a from..to range with an optional end.
+func numberRange(val string, integer bool) (*float64, *float64, error) {
+	parse := func(s string) (*float64, error) {
+		if s == "" {
+			return nil, nil
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, errors.New("expected a number or a range like 1..10")
+		}
+		if integer && n != math.Trunc(n) {
+			return nil, errors.New("expected a whole number")
+		}
+		return &n, nil
+	}
+
+	from, to, isRange := strings.Cut(val, "..")
+	if !isRange {
+		n, err := parse(val)
+		return n, n, err
+	}
+	if from == "" && to == "" {
+		return nil, nil, errors.New("a range needs at least one end")
+	}
+
+	low, err := parse(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	high, err := parse(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if low != nil && high != nil && *low > *high {
+		return nil, nil, errors.New("the range starts after its end")
+	}
+	return low, high, nil
+}
+
+// dateRange reads a partial date or a from..to range of them, a partial
+// date covers its whole period.
+func dateRange(val string) (*partialdate.Date, *partialdate.Date, error) {
+	parse := func(s string) (*partialdate.Date, error) {
+		if s == "" {
+			return nil, nil
+		}
+		d, err := partialdate.Parse(s)
+		if err != nil {
+			return nil, errors.New("expected a date like 2006, 2006-07 or 2006-07-16 or a range of them")
+		}
+		return &d, nil
+	}
+
+	from, to, isRange := strings.Cut(val, "..")
+	if !isRange {
+		d, err := parse(val)
+		return d, d, err
+	}
+	if from == "" && to == "" {
+		return nil, nil, errors.New("a range needs at least one end")
+	}
+
+	fromDate, err := parse(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	toDate, err := parse(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fromDate != nil && toDate != nil && fromDate.Time.After(toDate.End()) {
+		return nil, nil, errors.New("the range starts after its end")
+	}
+	return fromDate, toDate, nil
+}
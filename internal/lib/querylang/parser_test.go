@@ -0,0 +1,194 @@
+package querylang
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var testFields = Fields{
+	"group": Text,
+	"song":  Text,
+	"key":   Exact,
+	"year":  Integer,
+	"bpm":   Number,
+	"date":  Date,
+}
+
+// format writes the tree in a fully parenthesised form, open range ends are
+// left empty.
+func format(node Node) string {
+	join := func(nodes []Node, op string) string {
+		parts := make([]string, len(nodes))
+		for i, n := range nodes {
+			parts[i] = format(n)
+		}
+		return "(" + strings.Join(parts, " "+op+" ") + ")"
+	}
+
+	switch n := node.(type) {
+	case And:
+		return join(n.Nodes, "AND")
+	case Or:
+		return join(n.Nodes, "OR")
+	case Not:
+		return "NOT " + format(n.Node)
+	case *Match:
+		value := n.Value
+		switch n.Kind {
+		case Integer, Number:
+			value = bound(n.Min) + ".." + bound(n.Max)
+		case Date:
+			value = dateBound(n, true) + ".." + dateBound(n, false)
+		}
+		if n.Field == "" {
+			return value
+		}
+		return n.Field + ":" + value
+	}
+	return fmt.Sprintf("%T", node)
+}
+
+func bound(n *float64) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*n, 'f', -1, 64)
+}
+
+func dateBound(m *Match, from bool) string {
+	if from && m.From != nil {
+		return m.From.Time.Format("2006-01-02")
+	}
+	if !from && m.To != nil {
+		return m.To.End().Format("2006-01-02")
+	}
+	return ""
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "OR binds stronger than AND", query: "a b OR c", want: "(a AND (b OR c))"},
+		{name: "explicit AND", query: "a AND b OR c AND d", want: "(a AND (b OR c) AND d)"},
+		{name: "parentheses", query: "(a OR b) c", want: "((a OR b) AND c)"},
+		{name: "leading minus negates", query: "-song:live", want: "NOT song:live"},
+		{name: "minus inside a word is kept", query: "foo-bar", want: "foo-bar"},
+		{name: "minus inside a value is kept", query: "group:a-ha", want: "group:a-ha"},
+		{name: "minus after a space negates", query: "foo -bar", want: "(foo AND NOT bar)"},
+		{name: "NOT keyword", query: "NOT group:muse", want: "NOT group:muse"},
+		{name: "quoted keyword is a term", query: `"OR" a`, want: "(OR AND a)"},
+		{name: "quoted value with escapes", query: `song:"say \"hi\""`, want: `song:say "hi"`},
+		{name: "field names ignore case", query: "Group:muse", want: "group:muse"},
+		{name: "number", query: "bpm:120.5", want: "bpm:120.5..120.5"},
+		{name: "closed range", query: "year:2000..2010", want: "year:2000..2010"},
+		{name: "range open at the end", query: "year:2000..", want: "year:2000.."},
+		{name: "range open at the start", query: "year:..2010", want: "year:..2010"},
+		{name: "partial date covers its period", query: "date:2006-07", want: "date:2006-07-01..2006-07-31"},
+		{name: "date range open at the end", query: "date:2006..", want: "date:2006-01-01.."},
+		{name: "date range open at the start", query: "date:..2006", want: "date:..2006-12-31"},
+		{name: "multibyte words", query: "сплин OR ёлка", want: "(сплин OR ёлка)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := Parse(tt.query, testFields)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.query, err)
+			}
+			if got := format(node); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		pos   int
+		msg   string
+	}{
+		{name: "empty", query: "  ", pos: 1, msg: "query is empty"},
+		{name: "unclosed quote", query: `a "b c`, pos: 3, msg: "unterminated quoted string"},
+		{name: "unclosed quote after multibyte text", query: `ёлка "b`, pos: 6, msg: "unterminated quoted string"},
+		{name: "unclosed parenthesis", query: "a (b c", pos: 3, msg: "unclosed parenthesis"},
+		{name: "stray closing parenthesis", query: "a b)", pos: 4, msg: `unexpected ")"`},
+		{name: "empty parentheses", query: "()", pos: 2, msg: "expected a term before )"},
+		{name: "OR without a left term", query: "OR a", pos: 1, msg: "expected a term before OR"},
+		{name: "OR without a right term", query: "a OR", pos: 5, msg: "expected a term after OR"},
+		{name: "minus without a term", query: "a -", pos: 4, msg: "expected a term after -"},
+		{name: "field without a value", query: "group:", pos: 7, msg: "expected a value after group:"},
+		{name: "unknown field after multibyte text", query: "ёлка foo:bar", pos: 6, msg: `unknown field "foo"`},
+		{name: "invalid number after multibyte text", query: "сплин year:ёлка", pos: 12, msg: "invalid year value"},
+		{name: "fraction for an integer", query: "year:2000.5", pos: 6, msg: "expected a whole number"},
+		{name: "range without ends", query: "year:..", pos: 6, msg: "a range needs at least one end"},
+		{name: "range going back", query: "year:2010..2000", pos: 6, msg: "the range starts after its end"},
+		{name: "date range going back", query: "date:2006-08..2006-07", pos: 6, msg: "the range starts after its end"},
+		{name: "invalid date", query: "date:2006-13", pos: 6, msg: "invalid date value"},
+		{name: "empty quoted value", query: `song:""`, pos: 6, msg: "empty value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query, testFields)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want a SyntaxError", tt.query, err)
+			}
+			if syntaxErr.Pos != tt.pos || !strings.Contains(syntaxErr.Msg, tt.msg) {
+				t.Errorf("Parse(%q) error = %v, want position %d: %s", tt.query, err, tt.pos, tt.msg)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	terms := func(n int) string {
+		return strings.TrimSpace(strings.Repeat("a ", n))
+	}
+	nested := func(n int) string {
+		return strings.Repeat("(", n) + "a" + strings.Repeat(")", n)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		pos   int
+		msg   string
+	}{
+		{name: "terms at the limit", query: terms(maxTerms)},
+		{name: "terms over the limit", query: terms(maxTerms + 1), pos: 2*maxTerms + 1, msg: "terms"},
+		{name: "nesting at the limit", query: nested(maxDepth - 1)},
+		{name: "nesting over the limit", query: nested(maxDepth), pos: maxDepth + 1, msg: "nested too deeply"},
+		{name: "negations over the limit", query: strings.Repeat("-", maxDepth) + "a", pos: maxDepth, msg: "nested too deeply"},
+		{name: "length at the limit", query: strings.Repeat("ё", MaxLength)},
+		{name: "length over the limit", query: strings.Repeat("ё", MaxLength+1), pos: MaxLength + 1, msg: "longer than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.query, testFields)
+			if tt.msg == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				return
+			}
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse() error = %v, want a SyntaxError", err)
+			}
+			if syntaxErr.Pos != tt.pos || !strings.Contains(syntaxErr.Msg, tt.msg) {
+				t.Errorf("Parse() error = %v, want position %d: %s", err, tt.pos, tt.msg)
+			}
+		})
+	}
+}
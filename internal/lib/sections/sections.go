@@ -0,0 +1,206 @@
+package sections
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	Intro      = "intro"
+	Verse      = "verse"
+	PreChorus  = "pre-chorus"
+	Chorus     = "chorus"
+	PostChorus = "post-chorus"
+	Bridge     = "bridge"
+	Interlude  = "interlude"
+	Outro      = "outro"
+	Other      = "other"
+)
+
+// Types lists the section types in the order they are usually sung.
+var Types = []string{Intro, Verse, PreChorus, Chorus, PostChorus, Bridge, Interlude, Outro, Other}
+
+// aliases maps marker names that are spelled differently to a section type.
+var aliases = map[string]string{
+	"prechorus":    PreChorus,
+	"postchorus":   PostChorus,
+	"refrain":      Chorus,
+	"hook":         Chorus,
+	"break":        Interlude,
+	"instrumental": Interlude,
+}
+
+var markerRe = regexp.MustCompile(`^\[([^\[\]]+)\]$`)
+
+// Section is a labeled part of the lyrics. Index counts the sections of the
+// same type from 1, Position counts all sections from 1.
+type Section struct {
+	Position int    `json:"position"`
+	Type     string `json:"type"`
+	Index    int    `json:"index"`
+	Label    string `json:"label"`
+	Text     string `json:"text"`
+}
+
+type block struct {
+	marker string
+	lines  []string
+}
+
+// Parse splits the lyrics into sections. A block is either started by a
+// marker line like [Verse 2] or [Chorus: Artist] or separated by an empty
+// line. Blocks without a marker become verses unless their text repeats, a
+// repeated block is the chorus. A marker without text repeats the last
+// section of that type.
+func Parse(text string) []Section {
+	blocks := splitBlocks(text)
+
+	types := make([]string, len(blocks))
+	chorus := map[string]bool{}
+	seen := map[string]int{}
+	for i, b := range blocks {
+		if b.marker != "" {
+			types[i] = markerType(b.marker)
+			if types[i] == Chorus && len(b.lines) > 0 {
+				chorus[normalize(b.lines)] = true
+			}
+			continue
+		}
+		seen[normalize(b.lines)]++
+	}
+
+	for i, b := range blocks {
+		if b.marker != "" {
+			continue
+		}
+		key := normalize(b.lines)
+		if seen[key] > 1 || chorus[key] {
+			types[i] = Chorus
+		} else {
+			types[i] = Verse
+		}
+	}
+
+	result := []Section{}
+	counters := map[string]int{}
+	last := map[string]string{}
+	for i, b := range blocks {
+		typ := types[i]
+
+		body := strings.Join(b.lines, "\n")
+		if body == "" {
+			// a bare marker repeats the section
+			body = last[typ]
+			if body == "" {
+				continue
+			}
+		}
+		last[typ] = body
+
+		counters[typ]++
+		label := b.marker
+		if label == "" {
+			label = defaultLabel(typ, counters[typ])
+		}
+
+		result = append(result, Section{
+			Position: len(result) + 1,
+			Type:     typ,
+			Index:    counters[typ],
+			Label:    label,
+			Text:     body,
+		})
+	}
+
+	return result
+}
+
+// IsType reports whether typ is a known section type.
+func IsType(typ string) bool {
+	for _, t := range Types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func splitBlocks(text string) []block {
+	var blocks []block
+	var cur *block
+
+	flush := func() {
+		if cur != nil && (cur.marker != "" || len(cur.lines) > 0) {
+			blocks = append(blocks, *cur)
+		}
+		cur = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		trimmed := strings.TrimSpace(line)
+
+		if m := markerRe.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			cur = &block{marker: strings.TrimSpace(m[1])}
+			continue
+		}
+
+		if trimmed == "" {
+			// an empty line right after a marker does not end its section
+			if cur != nil && cur.marker != "" && len(cur.lines) == 0 {
+				continue
+			}
+			flush()
+			continue
+		}
+
+		if cur == nil {
+			cur = &block{}
+		}
+		cur.lines = append(cur.lines, line)
+	}
+	flush()
+
+	return blocks
+}
+
+// markerType gets the section type out of a marker, "Verse 2: Artist" is a
+// verse and "Pre Chorus" is a pre-chorus.
+func markerType(marker string) string {
+	name, _, _ := strings.Cut(marker, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimRightFunc(name, func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsSpace(r) || r == '#'
+	})
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '_' || r == '-'
+	}), "-")
+
+	if IsType(name) {
+		return name
+	}
+	if typ, ok := aliases[strings.ReplaceAll(name, "-", "")]; ok {
+		return typ
+	}
+	return Other
+}
+
+// defaultLabel follows the usual lyrics notation, only verses are numbered.
+func defaultLabel(typ string, index int) string {
+	label := strings.ToUpper(typ[:1]) + typ[1:]
+	if typ == Verse {
+		return fmt.Sprintf("%s %d", label, index)
+	}
+	return label
+}
+
+func normalize(lines []string) string {
+	norm := make([]string, len(lines))
+	for i, line := range lines {
+		norm[i] = strings.ToLower(strings.Join(strings.Fields(line), " "))
+	}
+	return strings.Join(norm, "\n")
+}
@@ -0,0 +1,84 @@
+package sections
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// outline writes the sections as one line per section: the position, the type
+// with its index, the label and the text.
+func outline(sections []Section) []string {
+	var result []string
+	for _, s := range sections {
+		result = append(result, fmt.Sprintf("%d %s%d %q %q", s.Position, s.Type, s.Index, s.Label, s.Text))
+	}
+	return result
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "blocks without markers are verses",
+			text: "a\nb\n\nc\nd",
+			want: []string{`1 verse1 "Verse 1" "a\nb"`, `2 verse2 "Verse 2" "c\nd"`},
+		},
+		{
+			name: "repeated block is the chorus",
+			text: "a\n\nla la\n\nb\n\nLa  LA",
+			want: []string{
+				`1 verse1 "Verse 1" "a"`,
+				`2 chorus1 "Chorus" "la la"`,
+				`3 verse2 "Verse 2" "b"`,
+				`4 chorus2 "Chorus" "La  LA"`,
+			},
+		},
+		{
+			name: "block with the text of a marked chorus is the chorus",
+			text: "[Refrain]\nla la\n\na\n\nla la",
+			want: []string{`1 chorus1 "Refrain" "la la"`, `2 verse1 "Verse 1" "a"`, `3 chorus2 "Chorus" "la la"`},
+		},
+		{
+			name: "bare marker repeats the last section of its type",
+			text: "[Chorus]\nla la\n\n[Verse 1]\na\n\n[Chorus]\n\n[Verse 2]\nb",
+			want: []string{
+				`1 chorus1 "Chorus" "la la"`,
+				`2 verse1 "Verse 1" "a"`,
+				`3 chorus2 "Chorus" "la la"`,
+				`4 verse2 "Verse 2" "b"`,
+			},
+		},
+		{
+			name: "bare marker without an earlier section is dropped",
+			text: "[Chorus]\n\n[Verse]\na",
+			want: []string{`1 verse1 "Verse" "a"`},
+		},
+		{
+			name: "marker types",
+			text: "[Pre Chorus: Artist]\na\n[Hook]\nb\n[Verse #2]\nc\n[Spoken]\nd",
+			want: []string{
+				`1 pre-chorus1 "Pre Chorus: Artist" "a"`,
+				`2 chorus1 "Hook" "b"`,
+				`3 verse1 "Verse #2" "c"`,
+				`4 other1 "Spoken" "d"`,
+			},
+		},
+		{
+			name: "empty line right after a marker",
+			text: "[Intro]\n\na\nb",
+			want: []string{`1 intro1 "Intro" "a\nb"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outline(Parse(tt.text)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
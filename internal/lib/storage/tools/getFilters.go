@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"music-library/internal/domain/dto"
+	"music-library/internal/lib/partialdate"
+	"music-library/internal/lib/querylang"
 	"strings"
 )
 
 func GetFilters(filters dto.Filters) (string, []any, error) {
 	var filterStr string
-	params := make([]any, 0, 5)
+	params := make([]any, 0, 15)
 
 	typeErr := errors.New("failed to convert filters")
 
@@ -21,8 +23,21 @@ func GetFilters(filters dto.Filters) (string, []any, error) {
 		if filterStr != "" {
 			filterStr += " AND "
 		}
-		params = append(params, "%"+strings.ToLower(toStr)+"%")
-		filterStr += fmt.Sprintf("LOWER(group_name) LIKE $%d", len(params))
+		if filters.Fuzzy != nil {
+			params = append(params, fuzzyValue(toStr))
+			filterStr += fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM song_artists sa
+				JOIN artists ca ON ca.id = sa.artist_id
+				WHERE sa.song_id = l.id AND LOWER(ca.name) %% $%d
+			)`, len(params))
+		} else {
+			params = append(params, "%"+strings.ToLower(toStr)+"%")
+			filterStr += fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM song_artists sa
+				JOIN artists ca ON ca.id = sa.artist_id
+				WHERE sa.song_id = l.id AND LOWER(ca.name) LIKE $%d
+			)`, len(params))
+		}
 	}
 
 	if filters.Song != nil {
@@ -33,8 +48,13 @@ func GetFilters(filters dto.Filters) (string, []any, error) {
 		if filterStr != "" {
 			filterStr += " AND "
 		}
-		params = append(params, "%"+strings.ToLower(toStr)+"%")
-		filterStr += fmt.Sprintf("LOWER(song) LIKE $%d", len(params))
+		if filters.Fuzzy != nil {
+			params = append(params, fuzzyValue(toStr))
+			filterStr += fmt.Sprintf("LOWER(l.song) %% $%d", len(params))
+		} else {
+			params = append(params, "%"+strings.ToLower(toStr)+"%")
+			filterStr += fmt.Sprintf("LOWER(l.song) LIKE $%d", len(params))
+		}
 	}
 
 	if filters.Text != nil {
@@ -46,24 +66,284 @@ func GetFilters(filters dto.Filters) (string, []any, error) {
 			filterStr += " AND "
 		}
 		params = append(params, "%"+strings.ToLower(toStr)+"%")
-		filterStr += fmt.Sprintf("LOWER(text) LIKE $%d", len(params))
+		filterStr += fmt.Sprintf("LOWER(l.text) LIKE $%d", len(params))
+	}
+
+	if filters.Album != nil {
+		toStr, ok := filters.Album.(string)
+		if !ok {
+			return "", nil, typeErr
+		}
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, "%"+strings.ToLower(toStr)+"%")
+		filterStr += fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM album_tracks at
+			JOIN albums al ON al.id = at.album_id
+			WHERE at.song_id = l.id AND LOWER(al.title) LIKE $%d
+		)`, len(params))
+	}
+
+	if filters.TagsAny != nil {
+		tags, ok := filters.TagsAny.([]string)
+		if !ok {
+			return "", nil, typeErr
+		}
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, tags)
+		filterStr += fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM song_tags st
+			JOIN tags t ON t.id = st.tag_id
+			WHERE st.song_id = l.id AND LOWER(t.name) = ANY($%d)
+		)`, len(params))
+	}
+
+	if filters.TagsAll != nil {
+		tags, ok := filters.TagsAll.([]string)
+		if !ok {
+			return "", nil, typeErr
+		}
+		for _, tag := range tags {
+			if filterStr != "" {
+				filterStr += " AND "
+			}
+			params = append(params, tag)
+			filterStr += fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM song_tags st
+				JOIN tags t ON t.id = st.tag_id
+				WHERE st.song_id = l.id AND LOWER(t.name) = $%d
+			)`, len(params))
+		}
+	}
+
+	if filters.Genre != nil {
+		toStr, ok := filters.Genre.(string)
+		if !ok {
+			return "", nil, typeErr
+		}
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, toStr)
+		filterStr += fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM song_genres sg
+			JOIN genres g ON g.id = sg.genre_id
+			WHERE sg.song_id = l.id AND LOWER(g.name) = $%d
+		)`, len(params))
+	}
+
+	if filters.DurationMin != nil {
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, filters.DurationMin)
+		filterStr += fmt.Sprintf("l.duration >= $%d", len(params))
+	}
+
+	if filters.DurationMax != nil {
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, filters.DurationMax)
+		filterStr += fmt.Sprintf("l.duration <= $%d", len(params))
+	}
+
+	if filters.BPMMin != nil {
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, filters.BPMMin)
+		filterStr += fmt.Sprintf("l.bpm >= $%d", len(params))
+	}
+
+	if filters.BPMMax != nil {
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, filters.BPMMax)
+		filterStr += fmt.Sprintf("l.bpm <= $%d", len(params))
+	}
+
+	if filters.Key != nil {
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, filters.Key)
+		filterStr += fmt.Sprintf("l.musical_key = $%d", len(params))
+	}
+
+	if filters.ISRC != nil {
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, filters.ISRC)
+		filterStr += fmt.Sprintf("l.isrc = $%d", len(params))
+	}
+
+	if filters.CreditedPerson != nil {
+		toStr, ok := filters.CreditedPerson.(string)
+		if !ok {
+			return "", nil, typeErr
+		}
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		params = append(params, "%"+strings.ToLower(toStr)+"%")
+		filterStr += fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM song_people sp
+			JOIN people p ON p.id = sp.person_id
+			WHERE sp.song_id = l.id AND LOWER(p.name) LIKE $%d
+		)`, len(params))
+	}
+
+	if filters.OriginalsOnly != nil {
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		filterStr += "NOT EXISTS (SELECT 1 FROM song_relations sr WHERE sr.song_id = l.id)"
+	}
+
+	if filters.Query != nil {
+		toStr, ok := filters.Query.(string)
+		if !ok {
+			return "", nil, typeErr
+		}
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		var match string
+		match, params = searchMatch(toStr, filters.QueryLang, params)
+		filterStr += match
 	}
 
 	if filters.ReleaseDateBefore != nil {
 		if filterStr != "" {
 			filterStr += " AND "
 		}
-		params = append(params, filters.ReleaseDateBefore)
-		filterStr += fmt.Sprintf("release_date <= $%d", len(params))
+		date, ok := filters.ReleaseDateBefore.(partialdate.Date)
+		if !ok {
+			return "", nil, typeErr
+		}
+		// a partial date matches only when its whole period is before the bound
+		params = append(params, date.End())
+		filterStr += fmt.Sprintf("l.release_date_end <= $%d", len(params))
 	}
 
 	if filters.ReleaseDateAfter != nil {
 		if filterStr != "" {
 			filterStr += " AND "
 		}
-		params = append(params, filters.ReleaseDateAfter)
-		filterStr += fmt.Sprintf("release_date >= $%d", len(params))
+		date, ok := filters.ReleaseDateAfter.(partialdate.Date)
+		if !ok {
+			return "", nil, typeErr
+		}
+		params = append(params, date.Time)
+		filterStr += fmt.Sprintf("l.release_date >= $%d", len(params))
+	}
+
+	if filters.Q != nil {
+		node, ok := filters.Q.(querylang.Node)
+		if !ok {
+			return "", nil, typeErr
+		}
+		if filterStr != "" {
+			filterStr += " AND "
+		}
+		var cond string
+		var err error
+		cond, params, err = compileQuery(node, params)
+		if err != nil {
+			return "", nil, err
+		}
+		filterStr += cond
 	}
 
 	return filterStr, params, nil
 }
+
+// GetRank returns the rank of the song against the query filter, NULL
+// without a query.
+func GetRank(filters dto.Filters, params []any) (string, []any) {
+	toStr, ok := filters.Query.(string)
+	if !ok {
+		return "NULL::float8", params
+	}
+
+	tsQuery, params := searchQuery(toStr, filters.QueryLang, params)
+	return fmt.Sprintf("ts_rank(l.search_vector, %s)::float8", tsQuery), params
+}
+
+// searchConfigs are the text search configurations lang_search_config maps
+// the languages of the lyrics to.
+var searchConfigs = []string{
+	"arabic", "danish", "dutch", "english", "finnish", "french", "german", "greek",
+	"hungarian", "indonesian", "irish", "italian", "lithuanian", "nepali", "norwegian",
+	"portuguese", "romanian", "russian", "simple", "spanish", "swedish", "tamil", "turkish",
+}
+
+// searchQuery parses the query in the text search configuration of the query
+// language, without one every song is matched in the configuration of its own
+// lyrics.
+func searchQuery(query string, lang any, params []any) (string, []any) {
+	params = append(params, query)
+	if lang == nil {
+		return fmt.Sprintf("websearch_to_tsquery(l.search_config, $%d)", len(params)), params
+	}
+
+	params = append(params, lang)
+	return fmt.Sprintf("websearch_to_tsquery(lang_search_config($%d), $%d)", len(params), len(params)-1), params
+}
+
+// searchMatch returns the condition for the songs that match the query. A
+// tsquery taken from the row rules out the index, so without a query language
+// the query is parsed once per configuration and each one matches only the
+// songs in it.
+func searchMatch(query string, lang any, params []any) (string, []any) {
+	if lang != nil {
+		tsQuery, params := searchQuery(query, lang, params)
+		return fmt.Sprintf("l.search_vector @@ %s", tsQuery), params
+	}
+
+	params = append(params, query)
+	branches := make([]string, 0, len(searchConfigs))
+	for _, config := range searchConfigs {
+		branches = append(branches, fmt.Sprintf(
+			"(l.search_config = '%[1]s'::regconfig AND l.search_vector @@ websearch_to_tsquery('%[1]s'::regconfig, $%[2]d))",
+			config, len(params)))
+	}
+	return "(" + strings.Join(branches, " OR ") + ")", params
+}
+
+// GetSimilarity returns how close the song is to the fuzzy group and song
+// filters, the mean of their trigram similarities, NULL without fuzzy.
+func GetSimilarity(filters dto.Filters, params []any) (string, []any) {
+	if filters.Fuzzy == nil {
+		return "NULL::float8", params
+	}
+
+	var scores []string
+	if toStr, ok := filters.Group.(string); ok {
+		params = append(params, fuzzyValue(toStr))
+		scores = append(scores, fmt.Sprintf(`COALESCE((
+			SELECT MAX(similarity(LOWER(ca.name), $%d)) FROM song_artists sa
+			JOIN artists ca ON ca.id = sa.artist_id
+			WHERE sa.song_id = l.id
+		), 0)`, len(params)))
+	}
+	if toStr, ok := filters.Song.(string); ok {
+		params = append(params, fuzzyValue(toStr))
+		scores = append(scores, fmt.Sprintf("similarity(LOWER(l.song), $%d)", len(params)))
+	}
+	if len(scores) == 0 {
+		return "NULL::float8", params
+	}
+
+	return fmt.Sprintf("((%s) / %d)::float8", strings.Join(scores, " + "), len(scores)), params
+}
+
+func fuzzyValue(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
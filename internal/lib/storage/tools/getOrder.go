@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"fmt"
+	"music-library/internal/domain/dto"
+	"strings"
+)
+
+// sortColumns are the expressions of the sort keys for library aliased as l
+// joined with artists aliased as a, rank and similarity are output columns.
+var sortColumns = map[string]string{
+	"id":           "l.id",
+	"group":        `a.name COLLATE natural_sort`,
+	"song":         `l.song COLLATE natural_sort`,
+	"release_date": "l.release_date",
+	"duration":     "l.duration",
+	"bpm":          "l.bpm",
+	"rank":         "rank",
+	"similarity":   "similarity",
+}
+
+// sortTypes are the SQL types of the sort keys, cursor values are cast to
+// them to compare with the columns.
+var sortTypes = map[string]string{
+	"id":           "integer",
+	"group":        "text",
+	"song":         "text",
+	"release_date": "date",
+	"duration":     "integer",
+	"bpm":          "numeric",
+	"rank":         "float8",
+	"similarity":   "float8",
+}
+
+// GetOrder returns the ORDER BY clause of the order made by dto.Order. Songs
+// without a value come last in both directions, backward reverses the order
+// to read the page before a cursor.
+func GetOrder(order []dto.SortKey, backward bool) (string, error) {
+	clauses := make([]string, 0, len(order))
+	for _, key := range order {
+		column, ok := sortColumns[key.Key]
+		if !ok {
+			return "", fmt.Errorf("failed to convert sort key %q", key.Key)
+		}
+		direction, nulls := "ASC", "LAST"
+		if key.Desc != backward {
+			direction = "DESC"
+		}
+		if backward {
+			nulls = "FIRST"
+		}
+		clauses = append(clauses, fmt.Sprintf("%s %s NULLS %s", column, direction, nulls))
+	}
+
+	return "ORDER BY " + strings.Join(clauses, ", "), nil
+}
+
+// GetSortValues returns an expression of the sort values of a song as a text
+// array, which a cursor keeps to find the song again. Rank and similarity are
+// the expressions of the output columns, which can not be referenced here.
+func GetSortValues(order []dto.SortKey, rank string, similarity string) (string, error) {
+	values := make([]string, 0, len(order))
+	for _, key := range order {
+		column, err := sortColumn(key.Key, rank, similarity)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, fmt.Sprintf("(%s)::text", column))
+	}
+
+	return fmt.Sprintf("ARRAY[%s]::text[]", strings.Join(values, ", ")), nil
+}
+
+// GetKeyset returns the condition for the songs after the sort values of the
+// keyset, or before them with Before, in the order. NULL sorts last in both
+// directions, so the condition is spelled out key by key rather than as a row
+// comparison.
+func GetKeyset(order []dto.SortKey, keyset dto.Keyset, rank string, similarity string, params []any) (string, []any, error) {
+	if len(keyset.Values) != len(order) {
+		return "", nil, fmt.Errorf("failed to convert keyset, got %d values for %d sort keys", len(keyset.Values), len(order))
+	}
+
+	var (
+		branches []string
+		equal    []string
+	)
+	for i, key := range order {
+		column, err := sortColumn(key.Key, rank, similarity)
+		if err != nil {
+			return "", nil, err
+		}
+
+		value := keyset.Values[i]
+		if value == nil {
+			if keyset.Before {
+				branches = append(branches, and(equal, fmt.Sprintf("%s IS NOT NULL", column)))
+			}
+			equal = append(equal, fmt.Sprintf("%s IS NULL", column))
+			continue
+		}
+
+		params = append(params, *value)
+		param := fmt.Sprintf("$%d::text::%s", len(params), sortTypes[key.Key])
+
+		op := ">"
+		if key.Desc != keyset.Before {
+			op = "<"
+		}
+		next := fmt.Sprintf("%s %s %s", column, op, param)
+		if !keyset.Before {
+			next = fmt.Sprintf("(%s OR %s IS NULL)", next, column)
+		}
+		branches = append(branches, and(equal, next))
+		equal = append(equal, fmt.Sprintf("%s = %s", column, param))
+	}
+
+	if len(branches) == 0 {
+		return "FALSE", params, nil
+	}
+	return "(" + strings.Join(branches, " OR ") + ")", params, nil
+}
+
+func sortColumn(key string, rank string, similarity string) (string, error) {
+	switch key {
+	case "rank":
+		return rank, nil
+	case "similarity":
+		return similarity, nil
+	}
+
+	column, ok := sortColumns[key]
+	if !ok {
+		return "", fmt.Errorf("failed to convert sort key %q", key)
+	}
+	return column, nil
+}
+
+func and(conditions []string, last string) string {
+	return "(" + strings.Join(append(conditions[:len(conditions):len(conditions)], last), " AND ") + ")"
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"music-library/internal/domain/dto"
+	"reflect"
+	"testing"
+)
+
+func TestGetOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    []dto.SortKey
+		backward bool
+		want     string
+	}{
+		{
+			name:  "ascending",
+			order: []dto.SortKey{{Key: "group"}, {Key: "id"}},
+			want:  "ORDER BY a.name COLLATE natural_sort ASC NULLS LAST, l.id ASC NULLS LAST",
+		},
+		{
+			name:  "descending keeps nulls last",
+			order: []dto.SortKey{{Key: "release_date", Desc: true}, {Key: "id"}},
+			want:  "ORDER BY l.release_date DESC NULLS LAST, l.id ASC NULLS LAST",
+		},
+		{
+			name:     "backward reverses the order",
+			order:    []dto.SortKey{{Key: "release_date", Desc: true}, {Key: "id"}},
+			backward: true,
+			want:     "ORDER BY l.release_date ASC NULLS FIRST, l.id DESC NULLS FIRST",
+		},
+		{
+			name:  "output columns",
+			order: []dto.SortKey{{Key: "rank", Desc: true}, {Key: "id"}},
+			want:  "ORDER BY rank DESC NULLS LAST, l.id ASC NULLS LAST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetOrder(tt.order, tt.backward)
+			if err != nil {
+				t.Fatalf("GetOrder() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetOrder() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := GetOrder([]dto.SortKey{{Key: "nope"}}, false); err == nil {
+		t.Error("GetOrder() of an unknown key error = nil, want an error")
+	}
+}
+
+func TestGetSortValues(t *testing.T) {
+	got, err := GetSortValues([]dto.SortKey{{Key: "similarity", Desc: true}, {Key: "id"}}, "r", "s")
+	if err != nil {
+		t.Fatalf("GetSortValues() error = %v", err)
+	}
+	if want := "ARRAY[(s)::text, (l.id)::text]::text[]"; got != want {
+		t.Errorf("GetSortValues() = %s, want %s", got, want)
+	}
+}
+
+func TestGetKeyset(t *testing.T) {
+	date, id := "2006-07-03", "5"
+	order := []dto.SortKey{{Key: "release_date", Desc: true}, {Key: "id"}}
+
+	tests := []struct {
+		name       string
+		order      []dto.SortKey
+		keyset     dto.Keyset
+		params     []any
+		want       string
+		wantParams []any
+	}{
+		{
+			name:       "after a value, songs without one follow",
+			order:      order,
+			keyset:     dto.Keyset{Values: []*string{&date, &id}},
+			want:       "(((l.release_date < $1::text::date OR l.release_date IS NULL)) OR (l.release_date = $1::text::date AND (l.id > $2::text::integer OR l.id IS NULL)))",
+			wantParams: []any{date, id},
+		},
+		{
+			name:       "before a value",
+			order:      order,
+			keyset:     dto.Keyset{Values: []*string{&date, &id}, Before: true},
+			want:       "((l.release_date > $1::text::date) OR (l.release_date = $1::text::date AND l.id < $2::text::integer))",
+			wantParams: []any{date, id},
+		},
+		{
+			name:       "after a null, only nulls follow",
+			order:      order,
+			keyset:     dto.Keyset{Values: []*string{nil, &id}},
+			want:       "((l.release_date IS NULL AND (l.id > $1::text::integer OR l.id IS NULL)))",
+			wantParams: []any{id},
+		},
+		{
+			name:       "before a null, every value comes first",
+			order:      order,
+			keyset:     dto.Keyset{Values: []*string{nil, &id}, Before: true},
+			want:       "((l.release_date IS NOT NULL) OR (l.release_date IS NULL AND l.id < $1::text::integer))",
+			wantParams: []any{id},
+		},
+		{
+			name:       "after the last null",
+			order:      []dto.SortKey{{Key: "bpm"}},
+			keyset:     dto.Keyset{Values: []*string{nil}},
+			want:       "FALSE",
+			wantParams: nil,
+		},
+		{
+			name:       "parameters follow the given ones",
+			order:      []dto.SortKey{{Key: "similarity", Desc: true}},
+			keyset:     dto.Keyset{Values: []*string{&id}},
+			params:     []any{"muse"},
+			want:       "(((s < $2::text::float8 OR s IS NULL)))",
+			wantParams: []any{"muse", id},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, params, err := GetKeyset(tt.order, tt.keyset, "r", "s", tt.params)
+			if err != nil {
+				t.Fatalf("GetKeyset() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetKeyset() = %s, want %s", got, tt.want)
+			}
+			if !reflect.DeepEqual(params, tt.wantParams) {
+				t.Errorf("GetKeyset() params = %v, want %v", params, tt.wantParams)
+			}
+		})
+	}
+
+	if _, _, err := GetKeyset(order, dto.Keyset{Values: []*string{&id}}, "r", "s", nil); err == nil {
+		t.Error("GetKeyset() with too few values error = nil, want an error")
+	}
+}
@@ -3,18 +3,19 @@ package tools
 import (
 	"fmt"
 	"music-library/internal/domain/dto"
+	"music-library/internal/lib/partialdate"
 )
 
-func GetUpdateParams(model dto.UpdateSong) (string, []any) {
-	params := make([]any, 0, 6)
+func GetUpdateParams(model dto.UpdateSong, artistID int) (string, []any) {
+	params := make([]any, 0, 10)
 	var setStr string
 
 	if model.Group != nil {
 		if setStr != "" {
 			setStr += ", "
 		}
-		params = append(params, model.Group)
-		setStr += fmt.Sprintf("group_name = $%d", len(params))
+		params = append(params, artistID)
+		setStr += fmt.Sprintf("artist_id = $%d", len(params))
 	}
 
 	if model.Song != nil {
@@ -37,16 +38,110 @@ func GetUpdateParams(model dto.UpdateSong) (string, []any) {
 		if setStr != "" {
 			setStr += ", "
 		}
-		params = append(params, model.ReleaseDate)
-		setStr += fmt.Sprintf("release_date = $%d", len(params))
+		date := model.ReleaseDate.(partialdate.Date)
+		params = append(params, date.Time, date.Precision)
+		setStr += fmt.Sprintf("release_date = $%d, release_date_precision = $%d", len(params)-1, len(params))
 	}
 
-	if model.Patronymic != nil {
+	if model.Duration != nil {
 		if setStr != "" {
 			setStr += ", "
 		}
-		params = append(params, model.Patronymic)
-		setStr += fmt.Sprintf("patronymic = $%d", len(params))
+		params = append(params, model.Duration)
+		setStr += fmt.Sprintf("duration = $%d", len(params))
+	}
+
+	if model.BPM != nil {
+		if setStr != "" {
+			setStr += ", "
+		}
+		params = append(params, model.BPM)
+		setStr += fmt.Sprintf("bpm = $%d", len(params))
+	}
+
+	if model.Key != nil {
+		if setStr != "" {
+			setStr += ", "
+		}
+		params = append(params, model.Key)
+		setStr += fmt.Sprintf("musical_key = $%d", len(params))
+	}
+
+	if model.ISRC != nil {
+		if setStr != "" {
+			setStr += ", "
+		}
+		params = append(params, model.ISRC)
+		setStr += fmt.Sprintf("isrc = $%d", len(params))
+	}
+
+	if model.Explicit != nil {
+		if setStr != "" {
+			setStr += ", "
+		}
+		params = append(params, model.Explicit)
+		setStr += fmt.Sprintf("explicit = $%d", len(params))
+	}
+
+	return setStr, params
+}
+
+func GetArtistUpdateParams(model dto.UpdateArtist) (string, []any) {
+	params := make([]any, 0, 4)
+	var setStr string
+
+	if model.Name != nil {
+		if setStr != "" {
+			setStr += ", "
+		}
+		params = append(params, model.Name)
+		setStr += fmt.Sprintf("name = $%d", len(params))
+	}
+
+	if model.SortName != nil {
+		if setStr != "" {
+			setStr += ", "
+		}
+		params = append(params, model.SortName)
+		setStr += fmt.Sprintf("sort_name = $%d", len(params))
+	}
+
+	if model.Country != nil {
+		if setStr != "" {
+			setStr += ", "
+		}
+		params = append(params, model.Country)
+		setStr += fmt.Sprintf("country = $%d", len(params))
+	}
+
+	if model.FormedYear != nil {
+		if setStr != "" {
+			setStr += ", "
+		}
+		params = append(params, model.FormedYear)
+		setStr += fmt.Sprintf("formed_year = $%d", len(params))
+	}
+
+	return setStr, params
+}
+
+func GetPlaylistUpdateParams(model dto.UpdatePlaylist) (string, []any) {
+	params := make([]any, 0, 3)
+	setStr := "updated_at = NOW()"
+
+	if model.Name != nil {
+		params = append(params, model.Name)
+		setStr += fmt.Sprintf(", name = $%d", len(params))
+	}
+
+	if model.Description != nil {
+		params = append(params, model.Description)
+		setStr += fmt.Sprintf(", description = $%d", len(params))
+	}
+
+	if model.AllowDuplicates != nil {
+		params = append(params, model.AllowDuplicates)
+		setStr += fmt.Sprintf(", allow_duplicates = $%d", len(params))
 	}
 
 	return setStr, params
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"music-library/internal/lib/querylang"
+	"strings"
+	"time"
+)
+
+// textConditions match the text and exact fields of the query language,
+// %[1]d is the parameter of the value.
+var textConditions = map[string]string{
+	"": `(LOWER(l.song) LIKE $%[1]d OR EXISTS (
+		SELECT 1 FROM song_artists sa
+		JOIN artists ca ON ca.id = sa.artist_id
+		WHERE sa.song_id = l.id AND LOWER(ca.name) LIKE $%[1]d
+	))`,
+	"group": `EXISTS (
+		SELECT 1 FROM song_artists sa
+		JOIN artists ca ON ca.id = sa.artist_id
+		WHERE sa.song_id = l.id AND LOWER(ca.name) LIKE $%[1]d
+	)`,
+	"song": "LOWER(l.song) LIKE $%[1]d",
+	"text": "LOWER(l.text) LIKE $%[1]d",
+	"album": `EXISTS (
+		SELECT 1 FROM album_tracks at
+		JOIN albums al ON al.id = at.album_id
+		WHERE at.song_id = l.id AND LOWER(al.title) LIKE $%[1]d
+	)`,
+	"person": `EXISTS (
+		SELECT 1 FROM song_people sp
+		JOIN people p ON p.id = sp.person_id
+		WHERE sp.song_id = l.id AND LOWER(p.name) LIKE $%[1]d
+	)`,
+	"tag": `EXISTS (
+		SELECT 1 FROM song_tags st
+		JOIN tags t ON t.id = st.tag_id
+		WHERE st.song_id = l.id AND LOWER(t.name) = $%[1]d
+	)`,
+	"genre": `EXISTS (
+		SELECT 1 FROM song_genres sg
+		JOIN genres g ON g.id = sg.genre_id
+		WHERE sg.song_id = l.id AND LOWER(g.name) = $%[1]d
+	)`,
+	"key":  "l.musical_key = $%[1]d",
+	"isrc": "l.isrc = $%[1]d",
+}
+
+// rangeColumns are the columns of the number fields.
+var rangeColumns = map[string]string{
+	"duration": "l.duration",
+	"bpm":      "l.bpm",
+}
+
+// compileQuery renders a parsed query as a condition on library aliased as l.
+// A negated term also matches songs that do not have the field.
+func compileQuery(node querylang.Node, params []any) (string, []any, error) {
+	switch n := node.(type) {
+	case querylang.And:
+		return compileNodes(n.Nodes, " AND ", params)
+	case querylang.Or:
+		return compileNodes(n.Nodes, " OR ", params)
+	case querylang.Not:
+		cond, params, err := compileQuery(n.Node, params)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("NOT COALESCE(%s, FALSE)", cond), params, nil
+	case *querylang.Match:
+		return compileMatch(n, params)
+	}
+	return "", nil, errors.New("failed to convert query")
+}
+
+func compileNodes(nodes []querylang.Node, sep string, params []any) (string, []any, error) {
+	conds := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		var cond string
+		var err error
+		cond, params, err = compileQuery(node, params)
+		if err != nil {
+			return "", nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return "(" + strings.Join(conds, sep) + ")", params, nil
+}
+
+func compileMatch(m *querylang.Match, params []any) (string, []any, error) {
+	var conds []string
+
+	switch m.Kind {
+	case querylang.Text, querylang.Exact:
+		tmpl, ok := textConditions[m.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("failed to convert query field %q", m.Field)
+		}
+		// exact values are normalized by dto.Filters.Validate
+		value := m.Value
+		if m.Kind == querylang.Text {
+			value = "%" + strings.ToLower(value) + "%"
+		}
+		params = append(params, value)
+		return fmt.Sprintf(tmpl, len(params)), params, nil
+	case querylang.Integer, querylang.Number:
+		if m.Field == "year" {
+			// a year matches when the whole release date is inside it
+			if m.Min != nil {
+				params = append(params, time.Date(int(*m.Min), time.January, 1, 0, 0, 0, 0, time.UTC))
+				conds = append(conds, fmt.Sprintf("l.release_date >= $%d", len(params)))
+			}
+			if m.Max != nil {
+				params = append(params, time.Date(int(*m.Max), time.December, 31, 0, 0, 0, 0, time.UTC))
+				conds = append(conds, fmt.Sprintf("l.release_date_end <= $%d", len(params)))
+			}
+			break
+		}
+		column, ok := rangeColumns[m.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("failed to convert query field %q", m.Field)
+		}
+		if m.Min != nil {
+			params = append(params, *m.Min)
+			conds = append(conds, fmt.Sprintf("%s >= $%d", column, len(params)))
+		}
+		if m.Max != nil {
+			params = append(params, *m.Max)
+			conds = append(conds, fmt.Sprintf("%s <= $%d", column, len(params)))
+		}
+	case querylang.Date:
+		if m.From != nil {
+			params = append(params, m.From.Time)
+			conds = append(conds, fmt.Sprintf("l.release_date >= $%d", len(params)))
+		}
+		if m.To != nil {
+			params = append(params, m.To.End())
+			conds = append(conds, fmt.Sprintf("l.release_date_end <= $%d", len(params)))
+		}
+	}
+
+	if len(conds) == 0 {
+		return "", nil, fmt.Errorf("failed to convert query field %q", m.Field)
+	}
+	return "(" + strings.Join(conds, " AND ") + ")", params, nil
+}
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"music-library/internal/lib/querylang"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testFields = querylang.Fields{
+	"song":  querylang.Text,
+	"key":   querylang.Exact,
+	"bpm":   querylang.Number,
+	"year":  querylang.Integer,
+	"date":  querylang.Date,
+	"mood":  querylang.Text,
+	"label": querylang.Exact,
+}
+
+func TestCompileQuery(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		want   string
+		params []any
+	}{
+		{
+			name:   "text",
+			query:  "song:Live",
+			want:   "LOWER(l.song) LIKE $1",
+			params: []any{"%live%"},
+		},
+		{
+			name:   "exact value",
+			query:  "key:Am",
+			want:   "l.musical_key = $1",
+			params: []any{"Am"},
+		},
+		{
+			name:   "negation matches songs without the field",
+			query:  "-song:live",
+			want:   "NOT COALESCE(LOWER(l.song) LIKE $1, FALSE)",
+			params: []any{"%live%"},
+		},
+		{
+			name:   "negated range",
+			query:  "-bpm:..100",
+			want:   "NOT COALESCE((l.bpm <= $1), FALSE)",
+			params: []any{100.0},
+		},
+		{
+			name:   "negated group",
+			query:  "-(song:a OR key:Am)",
+			want:   "NOT COALESCE((LOWER(l.song) LIKE $1 OR l.musical_key = $2), FALSE)",
+			params: []any{"%a%", "Am"},
+		},
+		{
+			name:   "AND and OR",
+			query:  "song:a song:b OR -song:c",
+			want:   "(LOWER(l.song) LIKE $1 AND (LOWER(l.song) LIKE $2 OR NOT COALESCE(LOWER(l.song) LIKE $3, FALSE)))",
+			params: []any{"%a%", "%b%", "%c%"},
+		},
+		{
+			name:   "year covers the whole release date",
+			query:  "year:2000..2010",
+			want:   "(l.release_date >= $1 AND l.release_date_end <= $2)",
+			params: []any{day(2000, time.January, 1), day(2010, time.December, 31)},
+		},
+		{
+			name:   "partial date",
+			query:  "date:2006-02",
+			want:   "(l.release_date >= $1 AND l.release_date_end <= $2)",
+			params: []any{day(2006, time.February, 1), day(2006, time.February, 28)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := querylang.Parse(tt.query, testFields)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.query, err)
+			}
+			got, params, err := compileQuery(node, nil)
+			if err != nil {
+				t.Fatalf("compileQuery(%q) error = %v", tt.query, err)
+			}
+			if got != tt.want {
+				t.Errorf("compileQuery(%q) = %s, want %s", tt.query, got, tt.want)
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("compileQuery(%q) params = %v, want %v", tt.query, params, tt.params)
+			}
+		})
+	}
+}
+
+func TestCompileQueryUnknownField(t *testing.T) {
+	for _, query := range []string{"mood:calm", "label:x", "-mood:calm"} {
+		t.Run(query, func(t *testing.T) {
+			node, err := querylang.Parse(query, testFields)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", query, err)
+			}
+			if _, _, err := compileQuery(node, nil); err == nil {
+				t.Errorf("compileQuery(%q) error = nil, want an error", query)
+			}
+		})
+	}
+}
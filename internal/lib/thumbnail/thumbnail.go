@@ -0,0 +1,93 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+// maxPixels keeps a small file that decodes into a huge image from eating
+// the memory.
+const maxPixels = 50_000_000
+
+var ErrTooManyPixels = errors.New("image has too many pixels")
+
+// Make decodes a JPEG, PNG, GIF or WebP image and returns a JPEG that fits into a
+// size x size square. Smaller images keep their size, transparent parts
+// become white.
+func Make(r io.ReadSeeker, size int) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	flat := image.NewRGBA(src.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, src.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scale(flat, size), &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scale shrinks the image by averaging the source pixels that fall into each
+// pixel of the result.
+func scale(src *image.RGBA, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return src
+	}
+
+	dw, dh := size, h*size/w
+	if h > w {
+		dw, dh = w*size/h, size
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+			var r, g, bl, n uint64
+			for sy := y0; sy < y1; sy++ {
+				off := src.PixOffset(b.Min.X+x0, b.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[off])
+					g += uint64(src.Pix[off+1])
+					bl += uint64(src.Pix[off+2])
+					off += 4
+					n++
+				}
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: 255})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,192 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+)
+
+func (s *LibraryService) SaveAlbum(ctx context.Context, model dto.Album, requestID string) (int, error) {
+	const op = "library.service.SaveAlbum"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	modelDB, err := model.ToDBModel()
+	if err != nil {
+		s.log.Error("failed to convert album to db model", sl.Err(err))
+		return 0, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := s.db.SaveAlbum(ctx, tx, modelDB, requestID)
+	if err != nil {
+		s.log.Error("failed to save album", sl.Err(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return 0, err
+	}
+
+	s.log.Info("album was successfully saved", slog.Int("id", id))
+	return id, nil
+}
+
+func (s *LibraryService) GetAlbums(ctx context.Context, artistID int, limit int, offset int, requestID string) ([]models.Album, error) {
+	const op = "library.service.GetAlbums"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	albums, err := s.db.GetAlbums(ctx, tx, artistID, limit, offset, requestID)
+	if err != nil {
+		s.log.Error("failed to get albums", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("albums successfully fetched", slog.Int("albums_count", len(albums)))
+	return albums, nil
+}
+
+func (s *LibraryService) GetAlbum(ctx context.Context, albumID int, requestID string) (models.Album, error) {
+	const op = "library.service.GetAlbum"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return models.Album{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	album, err := s.db.GetAlbum(ctx, tx, albumID, requestID)
+	if err != nil {
+		s.log.Error("failed to get album", sl.Err(err))
+		return models.Album{}, err
+	}
+
+	s.log.Info("album successfully fetched", slog.Int("album_id", albumID))
+	return album, nil
+}
+
+func (s *LibraryService) DeleteAlbum(ctx context.Context, albumID int, requestID string) error {
+	const op = "library.service.DeleteAlbum"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.DeleteAlbum(ctx, tx, albumID, requestID); err != nil {
+		s.log.Error("failed to delete album", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("album was successfully deleted")
+	return nil
+}
+
+func (s *LibraryService) AddAlbumTrack(ctx context.Context, albumID int, track dto.AddAlbumTrack, requestID string) (int, error) {
+	const op = "library.service.AddAlbumTrack"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	position, err := s.db.AddAlbumTrack(ctx, tx, albumID, track, requestID)
+	if err != nil {
+		s.log.Error("failed to add album track", sl.Err(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return 0, err
+	}
+
+	s.log.Info("track was successfully added to album", slog.Int("position", position))
+	return position, nil
+}
+
+func (s *LibraryService) ReorderAlbumTracks(ctx context.Context, albumID int, tracks dto.ReorderAlbumTracks, requestID string) error {
+	const op = "library.service.ReorderAlbumTracks"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.ReorderAlbumTracks(ctx, tx, albumID, tracks.SongIDs, requestID); err != nil {
+		s.log.Error("failed to reorder album tracks", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("album tracks were successfully reordered")
+	return nil
+}
+
+func (s *LibraryService) RemoveAlbumTrack(ctx context.Context, albumID int, songID int, requestID string) error {
+	const op = "library.service.RemoveAlbumTrack"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.RemoveAlbumTrack(ctx, tx, albumID, songID, requestID); err != nil {
+		s.log.Error("failed to remove album track", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("track was successfully removed from album")
+	return nil
+}
@@ -0,0 +1,133 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+)
+
+func (s *LibraryService) SaveArtist(ctx context.Context, model dto.Artist, requestID string) (int, error) {
+	const op = "library.service.SaveArtist"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := s.db.SaveArtist(ctx, tx, model, requestID)
+	if err != nil {
+		s.log.Error("failed to save artist", sl.Err(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return 0, err
+	}
+
+	s.log.Info("artist was successfully saved", slog.Int("id", id))
+	return id, nil
+}
+
+func (s *LibraryService) GetArtists(ctx context.Context, limit int, offset int, requestID string) ([]models.Artist, error) {
+	const op = "library.service.GetArtists"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	artists, err := s.db.GetArtists(ctx, tx, limit, offset, requestID)
+	if err != nil {
+		s.log.Error("failed to get artists", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("artists successfully fetched", slog.Int("artists_count", len(artists)))
+	return artists, nil
+}
+
+func (s *LibraryService) GetArtist(ctx context.Context, artistID int, requestID string) (models.Artist, error) {
+	const op = "library.service.GetArtist"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return models.Artist{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	artist, err := s.db.GetArtist(ctx, tx, artistID, requestID)
+	if err != nil {
+		s.log.Error("failed to get artist", sl.Err(err))
+		return models.Artist{}, err
+	}
+
+	s.log.Info("artist successfully fetched", slog.Int("artist_id", artistID))
+	return artist, nil
+}
+
+func (s *LibraryService) UpdateArtist(ctx context.Context, updateModel dto.UpdateArtist, requestID string) error {
+	const op = "library.service.UpdateArtist"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.UpdateArtist(ctx, tx, updateModel, requestID); err != nil {
+		s.log.Error("failed to update artist", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("artist was successfully updated")
+	return nil
+}
+
+func (s *LibraryService) DeleteArtist(ctx context.Context, artistID int, requestID string) error {
+	const op = "library.service.DeleteArtist"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.DeleteArtist(ctx, tx, artistID, requestID); err != nil {
+		s.log.Error("failed to delete artist", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("artist was successfully deleted")
+	return nil
+}
@@ -0,0 +1,241 @@
+package library
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/thumbnail"
+	"music-library/internal/storage"
+	"net/http"
+	"strings"
+)
+
+// attachmentTypes are the sniffed content types that can be uploaded with the
+// kind they get when none is given.
+var attachmentTypes = map[string]string{
+	"image/jpeg":      dto.KindArtwork,
+	"image/png":       dto.KindArtwork,
+	"image/gif":       dto.KindArtwork,
+	"image/webp":      dto.KindArtwork,
+	"application/pdf": dto.KindSheetMusic,
+}
+
+// SaveAttachment stores the uploaded file of the song. The content type is
+// sniffed from the data rather than taken from the client, images get a
+// thumbnail when the format can be decoded.
+func (s *LibraryService) SaveAttachment(ctx context.Context, songID int, attachment dto.Attachment, r io.Reader, requestID string) (models.Attachment, error) {
+	const op = "library.service.SaveAttachment"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		s.log.Error("failed to read upload", sl.Err(err))
+		return models.Attachment{}, err
+	}
+	if len(head) == 0 {
+		s.log.Error("empty upload")
+		return models.Attachment{}, fmt.Errorf("file is empty")
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	kind, ok := attachmentTypes[contentType]
+	if !ok {
+		s.log.Error("unsupported content type", slog.String("content_type", contentType))
+		return models.Attachment{}, storage.ErrUnsupportedMediaType
+	}
+	if attachment.Kind != "" {
+		if attachment.Kind == dto.KindArtwork && kind != dto.KindArtwork {
+			s.log.Error("artwork is not an image", slog.String("content_type", contentType))
+			return models.Attachment{}, fmt.Errorf("artwork must be an image, got %s", contentType)
+		}
+		kind = attachment.Kind
+	}
+
+	model := dto.AttachmentDB{
+		SongID:      songID,
+		Kind:        kind,
+		Filename:    attachment.Filename,
+		ContentType: contentType,
+		BlobKey:     blobKey(songID),
+	}
+
+	hash := sha256.New()
+	model.Size, err = s.blobs.Put(ctx, model.BlobKey, io.TeeReader(br, hash), s.attachments.MaxSize)
+	if err != nil {
+		s.log.Error("failed to store attachment", sl.Err(err))
+		return models.Attachment{}, err
+	}
+	model.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	keys := []string{model.BlobKey}
+
+	if strings.HasPrefix(contentType, "image/") {
+		if thumbKey, err := s.saveThumbnail(ctx, model.BlobKey, songID); err != nil {
+			s.log.Warn("failed to make thumbnail", sl.Err(err))
+		} else {
+			model.ThumbnailKey = &thumbKey
+			keys = append(keys, thumbKey)
+		}
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		s.deleteBlobs(ctx, keys)
+		return models.Attachment{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	att, err := s.db.SaveAttachment(ctx, tx, model, requestID)
+	if err != nil {
+		s.log.Error("failed to save attachment", sl.Err(err))
+		s.deleteBlobs(ctx, keys)
+		return models.Attachment{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		s.deleteBlobs(ctx, keys)
+		return models.Attachment{}, err
+	}
+
+	s.log.Info("attachment was successfully saved", slog.Int("id", att.ID), slog.Int64("size", att.Size))
+	return att, nil
+}
+
+func (s *LibraryService) GetAttachments(ctx context.Context, songID int, requestID string) ([]models.Attachment, error) {
+	const op = "library.service.GetAttachments"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	attachments, err := s.db.GetAttachments(ctx, tx, songID, requestID)
+	if err != nil {
+		s.log.Error("failed to get attachments", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("attachments successfully fetched", slog.Int("attachments_count", len(attachments)))
+	return attachments, nil
+}
+
+// OpenAttachment returns the attachment with its data or the data of its
+// thumbnail, the caller closes the reader.
+func (s *LibraryService) OpenAttachment(ctx context.Context, attachmentID int, thumb bool, requestID string) (models.Attachment, io.ReadSeekCloser, error) {
+	const op = "library.service.OpenAttachment"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return models.Attachment{}, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	att, err := s.db.GetAttachment(ctx, tx, attachmentID, requestID)
+	if err != nil {
+		s.log.Error("failed to get attachment", sl.Err(err))
+		return models.Attachment{}, nil, err
+	}
+
+	key := att.BlobKey
+	if thumb {
+		if att.ThumbnailKey == nil {
+			s.log.Error("attachment has no thumbnail", slog.Int("id", att.ID))
+			return models.Attachment{}, nil, fmt.Errorf("attachment has no thumbnail")
+		}
+		key = *att.ThumbnailKey
+	}
+
+	file, err := s.blobs.Open(ctx, key)
+	if err != nil {
+		s.log.Error("failed to open attachment", sl.Err(err))
+		return models.Attachment{}, nil, err
+	}
+
+	s.log.Info("attachment successfully opened", slog.Int("id", att.ID), slog.Bool("thumbnail", thumb))
+	return att, file, nil
+}
+
+func (s *LibraryService) DeleteAttachment(ctx context.Context, attachmentID int, requestID string) error {
+	const op = "library.service.DeleteAttachment"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	keys, err := s.db.DeleteAttachment(ctx, tx, attachmentID, requestID)
+	if err != nil {
+		s.log.Error("failed to delete attachment", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.deleteBlobs(ctx, keys)
+
+	s.log.Info("attachment was successfully deleted", slog.Int("id", attachmentID))
+	return nil
+}
+
+func (s *LibraryService) saveThumbnail(ctx context.Context, key string, songID int) (string, error) {
+	file, err := s.blobs.Open(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := thumbnail.Make(file, s.attachments.ThumbnailSize)
+	if err != nil {
+		return "", err
+	}
+
+	thumbKey := blobKey(songID) + "-thumb"
+	if _, err := s.blobs.Put(ctx, thumbKey, bytes.NewReader(data), 0); err != nil {
+		return "", err
+	}
+	return thumbKey, nil
+}
+
+// deleteBlobs removes blobs whose rows are already gone, a failure only
+// leaves an unreferenced file behind.
+func (s *LibraryService) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			s.log.Warn("failed to delete blob", slog.String("key", key), sl.Err(err))
+		}
+	}
+}
+
+func blobKey(songID int) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return fmt.Sprintf("songs/%d/%s", songID, hex.EncodeToString(b))
+}
@@ -0,0 +1,62 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+)
+
+func (s *LibraryService) AddSongCredit(ctx context.Context, songID int, credit dto.SongCredit, requestID string) (int, error) {
+	const op = "library.service.AddSongCredit"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	artistID, err := s.db.AddSongCredit(ctx, tx, songID, credit, requestID)
+	if err != nil {
+		s.log.Error("failed to add song credit", sl.Err(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return 0, err
+	}
+
+	s.log.Info("song credit was successfully added", slog.Int("artist_id", artistID))
+	return artistID, nil
+}
+
+func (s *LibraryService) RemoveSongCredit(ctx context.Context, songID int, artistID int, role string, requestID string) error {
+	const op = "library.service.RemoveSongCredit"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.RemoveSongCredit(ctx, tx, songID, artistID, role, requestID); err != nil {
+		s.log.Error("failed to remove song credit", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("song credit was successfully removed")
+	return nil
+}
@@ -0,0 +1,69 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+)
+
+func (s *LibraryService) GetDuplicates(ctx context.Context, limit int, offset int, requestID string) ([]models.Duplicates, error) {
+	const op = "library.service.GetDuplicates"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	duplicates, err := s.db.GetDuplicates(ctx, tx, limit, offset, requestID)
+	if err != nil {
+		s.log.Error("failed to get duplicates", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("duplicates successfully fetched", slog.Int("duplicates_count", len(duplicates)))
+	return duplicates, nil
+}
+
+// MergeSong folds the song into another one and deletes it for good, its
+// links, tags, genres, attachments, playlist entries, album tracks, credits,
+// translations, synced lyrics and relations are kept on the target.
+func (s *LibraryService) MergeSong(ctx context.Context, songID int, merge dto.MergeSong, requestID string) error {
+	const op = "library.service.MergeSong"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.MergeSong(ctx, tx, songID, merge.Into, requestID); err != nil {
+		s.log.Error("failed to merge song", sl.Err(err))
+		return err
+	}
+
+	blobKeys, err := s.purgeSong(ctx, tx, songID, requestID)
+	if err != nil {
+		s.log.Error("failed to purge merged song", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.deleteBlobs(ctx, blobKeys)
+
+	s.log.Info("song was successfully merged", slog.Int("song_id", songID), slog.Int("into_id", merge.Into))
+	return nil
+}
@@ -0,0 +1,190 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/audiotag"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/storage"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// audioExtensions are the files an import reads, other files are skipped.
+var audioExtensions = map[string]bool{
+	".mp3":  true,
+	".flac": true,
+	".m4a":  true,
+	".mp4":  true,
+}
+
+// ImportSongs imports a directory inside the configured import directory.
+func (s *LibraryService) ImportSongs(ctx context.Context, model dto.Import, requestID string) (models.ImportReport, error) {
+	return s.ImportDir(ctx, filepath.Join(s.imports.Dir, model.Dir), model.DryRun, requestID)
+}
+
+// ImportDir walks the directory and saves a song for every audio file whose
+// tags have an artist, a title and a date. Every file is saved in its own
+// transaction, a dry run rolls it back so the report still tells new songs
+// from duplicates.
+func (s *LibraryService) ImportDir(ctx context.Context, dir string, dryRun bool, requestID string) (models.ImportReport, error) {
+	const op = "library.service.ImportDir"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		s.log.Error("import directory not found", slog.String("dir", dir))
+		return models.ImportReport{}, errors.New("import directory not found")
+	}
+
+	report := models.ImportReport{DryRun: dryRun, Files: []models.ImportFile{}}
+	// files saved by this import by dedup key, a dry run does not keep them
+	// in the library
+	imported := map[string]string{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// an unreadable entry is reported like an unreadable file, the
+			// rest of the directory is still imported
+			if path == dir {
+				return err
+			}
+			rel, relErr := filepath.Rel(dir, path)
+			if relErr != nil {
+				return relErr
+			}
+			s.log.Warn("failed to read import path", slog.String("path", rel), sl.Err(err))
+			report.Invalid++
+			report.Files = append(report.Files, models.ImportFile{
+				Path:   filepath.ToSlash(rel),
+				Status: models.ImportInvalid,
+				Error:  err.Error(),
+			})
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !audioExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		file, err := s.importFile(ctx, path, filepath.ToSlash(rel), dryRun, imported, requestID)
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", rel, err)
+		}
+
+		switch file.Status {
+		case models.ImportNew:
+			report.New++
+		case models.ImportDuplicate:
+			report.Duplicates++
+		case models.ImportInvalid:
+			report.Invalid++
+		}
+		report.Files = append(report.Files, file)
+		return nil
+	})
+	if err != nil {
+		s.log.Error("failed to import directory", sl.Err(err))
+		return models.ImportReport{}, err
+	}
+
+	s.log.Info("directory was successfully imported", slog.Bool("dry_run", dryRun), slog.Int("new", report.New),
+		slog.Int("duplicates", report.Duplicates), slog.Int("invalid", report.Invalid))
+	return report, nil
+}
+
+// importFile saves the song of a single file. Unreadable tags make the file
+// invalid, only database errors fail the import.
+func (s *LibraryService) importFile(ctx context.Context, path string, rel string, dryRun bool, imported map[string]string, requestID string) (models.ImportFile, error) {
+	file := models.ImportFile{Path: rel, Status: models.ImportInvalid}
+
+	tags, err := readTags(path)
+	if err != nil {
+		file.Error = err.Error()
+		return file, nil
+	}
+	file.Format = tags.Format
+
+	model, err := dto.SongFromTags(tags)
+	if err != nil {
+		file.Error = err.Error()
+		return file, nil
+	}
+	file.Group, file.Song = model.Group, model.Song
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return file, err
+	}
+	defer tx.Rollback(ctx)
+
+	key, err := s.db.GetDedupKey(ctx, tx, model.Group, model.Song, requestID)
+	if err != nil {
+		s.log.Error("failed to get dedup key", sl.Err(err))
+		return file, err
+	}
+	if prev, ok := imported[key]; ok {
+		file.Status, file.DuplicateFile = models.ImportDuplicate, prev
+		return file, nil
+	}
+
+	id, err := s.db.SaveSong(ctx, tx, model, requestID)
+	if err != nil {
+		var dupErr *storage.DuplicateSongError
+		if errors.As(err, &dupErr) {
+			file.Status, file.DuplicateOf = models.ImportDuplicate, dupErr.ID
+			return file, nil
+		}
+		s.log.Error("failed to save song", sl.Err(err))
+		return file, err
+	}
+
+	if model.Album != nil {
+		if _, err := s.db.SaveSongAlbum(ctx, tx, id, *model.Album, requestID); err != nil {
+			s.log.Error("failed to link song to album", sl.Err(err))
+			return file, err
+		}
+	}
+
+	file.Status = models.ImportNew
+	imported[key] = rel
+	if dryRun {
+		return file, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return file, err
+	}
+	file.SongID = id
+
+	return file, nil
+}
+
+func readTags(path string) (audiotag.Tags, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return audiotag.Tags{}, err
+	}
+	defer f.Close()
+
+	return audiotag.Read(f)
+}
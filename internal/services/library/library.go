@@ -3,6 +3,7 @@ package library
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -10,31 +11,107 @@ import (
 	"music-library/internal/config"
 	"music-library/internal/domain/dto"
 	"music-library/internal/domain/models"
+	"music-library/internal/lib/cursor"
 	"music-library/internal/lib/logger/sl"
 	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/lrc"
+	"music-library/internal/lib/sections"
+	"music-library/internal/storage/blob"
 	"net/http"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type LibraryService struct {
-	log  *slog.Logger
-	pool *pgxpool.Pool
-	db   LibraryDB
-	cfg  config.LibraryServer
+	log         *slog.Logger
+	pool        *pgxpool.Pool
+	db          LibraryDB
+	cfg         config.LibraryServer
+	blobs       blob.Store
+	attachments config.Attachments
+	imports     config.Import
+	search      config.Search
+	cursors     *cursor.Signer
 }
 
 type LibraryDB interface {
 	SaveSong(ctx context.Context, tx pgx.Tx, model dto.SongDB, requestID string) (int, error)
-	GetLibray(ctx context.Context, tx pgx.Tx, filters dto.Filters, limit int, offset int, requestID string) ([]models.Song, error)
-	GetSongText(ctx context.Context, tx pgx.Tx, songID int, requestID string) (string, error)
+	GetLibray(ctx context.Context, tx pgx.Tx, filters dto.Filters, sort []dto.SortKey, limit int, offset int, keyset *dto.Keyset, requestID string) ([]models.Song, error)
+	CountLibrary(ctx context.Context, tx pgx.Tx, filters dto.Filters, estimate bool, requestID string) (int, error)
+	GetLibraryFacets(ctx context.Context, tx pgx.Tx, filters dto.Filters, facets []string, requestID string) (map[string][]models.FacetValue, error)
+	GetSongText(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) (string, string, error)
+	GetSongSections(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) ([]sections.Section, error)
 	DeleteSong(ctx context.Context, tx pgx.Tx, songID int, requestID string) error
 	UpdateSong(ctx context.Context, tx pgx.Tx, updateModel dto.UpdateSong, requestID string) error
+
+	SaveArtist(ctx context.Context, tx pgx.Tx, model dto.Artist, requestID string) (int, error)
+	GetArtists(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.Artist, error)
+	GetArtist(ctx context.Context, tx pgx.Tx, artistID int, requestID string) (models.Artist, error)
+	UpdateArtist(ctx context.Context, tx pgx.Tx, updateModel dto.UpdateArtist, requestID string) error
+	DeleteArtist(ctx context.Context, tx pgx.Tx, artistID int, requestID string) error
+
+	SaveAlbum(ctx context.Context, tx pgx.Tx, model dto.AlbumDB, requestID string) (int, error)
+	GetAlbums(ctx context.Context, tx pgx.Tx, artistID int, limit int, offset int, requestID string) ([]models.Album, error)
+	GetAlbum(ctx context.Context, tx pgx.Tx, albumID int, requestID string) (models.Album, error)
+	DeleteAlbum(ctx context.Context, tx pgx.Tx, albumID int, requestID string) error
+	AddAlbumTrack(ctx context.Context, tx pgx.Tx, albumID int, track dto.AddAlbumTrack, requestID string) (int, error)
+	ReorderAlbumTracks(ctx context.Context, tx pgx.Tx, albumID int, songIDs []int, requestID string) error
+	RemoveAlbumTrack(ctx context.Context, tx pgx.Tx, albumID int, songID int, requestID string) error
+	SaveSongAlbum(ctx context.Context, tx pgx.Tx, songID int, album dto.AlbumDB, requestID string) (int, error)
+
+	AddSongTags(ctx context.Context, tx pgx.Tx, songID int, tags []string, requestID string) error
+	RemoveSongTag(ctx context.Context, tx pgx.Tx, songID int, tag string, requestID string) error
+	AddSongGenres(ctx context.Context, tx pgx.Tx, songID int, genres []string, requestID string) error
+	RemoveSongGenre(ctx context.Context, tx pgx.Tx, songID int, genre string, requestID string) error
+
+	SavePlaylist(ctx context.Context, tx pgx.Tx, model dto.Playlist, requestID string) (int, error)
+	GetPlaylists(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.Playlist, error)
+	GetPlaylist(ctx context.Context, tx pgx.Tx, playlistID int, requestID string) (models.Playlist, error)
+	UpdatePlaylist(ctx context.Context, tx pgx.Tx, updateModel dto.UpdatePlaylist, requestID string) error
+	DeletePlaylist(ctx context.Context, tx pgx.Tx, playlistID int, requestID string) error
+	AddPlaylistEntry(ctx context.Context, tx pgx.Tx, playlistID int, entry dto.AddPlaylistEntry, requestID string) (int, int, error)
+	RemovePlaylistEntry(ctx context.Context, tx pgx.Tx, playlistID int, entryID int, requestID string) error
+	MovePlaylistEntry(ctx context.Context, tx pgx.Tx, playlistID int, entryID int, position int, requestID string) (int, error)
+	RemoveSongFromPlaylists(ctx context.Context, tx pgx.Tx, songID int, requestID string) error
+	GetTrash(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.TrashItem, error)
+	RestoreSong(ctx context.Context, tx pgx.Tx, songID int, requestID string) error
+	PurgeSong(ctx context.Context, tx pgx.Tx, songID int, requestID string) error
+	GetExpiredTrash(ctx context.Context, tx pgx.Tx, before time.Time, requestID string) ([]int, error)
+	AddSongCredit(ctx context.Context, tx pgx.Tx, songID int, credit dto.SongCredit, requestID string) (int, error)
+	RemoveSongCredit(ctx context.Context, tx pgx.Tx, songID int, artistID int, role string, requestID string) error
+	GetPeople(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.Person, error)
+	GetPersonSongs(ctx context.Context, tx pgx.Tx, personID int, limit int, offset int, requestID string) ([]models.PersonSong, error)
+	AddPersonCredit(ctx context.Context, tx pgx.Tx, songID int, credit dto.PersonCredit, requestID string) (int, error)
+	RemovePersonCredit(ctx context.Context, tx pgx.Tx, songID int, personID int, role string, requestID string) error
+	GetLyrics(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]models.Lyrics, error)
+	SaveTranslation(ctx context.Context, tx pgx.Tx, songID int, lang string, text string, requestID string) error
+	DeleteTranslation(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) error
+	SetOriginalLang(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) error
+	GetSyncedLyrics(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) ([]lrc.Line, string, error)
+	SaveSyncedLyrics(ctx context.Context, tx pgx.Tx, songID int, lang string, lines []lrc.Line, requestID string) error
+	DeleteSyncedLyrics(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) error
+	GetDuplicates(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.Duplicates, error)
+	MergeSong(ctx context.Context, tx pgx.Tx, songID int, intoID int, requestID string) error
+	GetDedupKey(ctx context.Context, tx pgx.Tx, group string, song string, requestID string) (string, error)
+	SaveAttachment(ctx context.Context, tx pgx.Tx, model dto.AttachmentDB, requestID string) (models.Attachment, error)
+	GetAttachments(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]models.Attachment, error)
+	GetAttachment(ctx context.Context, tx pgx.Tx, attachmentID int, requestID string) (models.Attachment, error)
+	DeleteAttachment(ctx context.Context, tx pgx.Tx, attachmentID int, requestID string) ([]string, error)
+	GetSongBlobKeys(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]string, error)
+	LinkSongs(ctx context.Context, tx pgx.Tx, songID int, relation dto.SongRelation, requestID string) error
+	UnlinkSongs(ctx context.Context, tx pgx.Tx, songID int, originalID int, requestID string) error
+	GetSongVersions(ctx context.Context, tx pgx.Tx, songID int, requestID string) (models.SongVersions, error)
+	GetSongLinks(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]models.Link, error)
+	AddSongLink(ctx context.Context, tx pgx.Tx, songID int, link dto.LinkDB, requestID string) (int, error)
+	RemoveSongLink(ctx context.Context, tx pgx.Tx, songID int, linkID int, requestID string) error
+	GetSongRevisions(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]models.SongRevision, error)
+	GetSongRevision(ctx context.Context, tx pgx.Tx, songID int, revision int, requestID string) (models.SongRevision, error)
 }
 
-func NewLibraryService(log *slog.Logger, pool *pgxpool.Pool, db LibraryDB, cfg config.LibraryServer) *LibraryService {
-	return &LibraryService{log: log, pool: pool, db: db, cfg: cfg}
+func NewLibraryService(log *slog.Logger, pool *pgxpool.Pool, db LibraryDB, cfg config.LibraryServer, blobs blob.Store, attachments config.Attachments, imports config.Import, search config.Search) *LibraryService {
+	return &LibraryService{log: log, pool: pool, db: db, cfg: cfg, blobs: blobs, attachments: attachments, imports: imports, search: search, cursors: cursor.NewSigner([]byte(search.CursorSecret))}
 }
 
 func (s *LibraryService) SaveSong(ctx context.Context, model dto.SongRequest, requestID string) (int, error) {
@@ -101,6 +178,13 @@ func (s *LibraryService) SaveSong(ctx context.Context, model dto.SongRequest, re
 		return 0, err
 	}
 
+	if modelDB.Album != nil {
+		if _, err := s.db.SaveSongAlbum(ctx, tx, id, *modelDB.Album, requestID); err != nil {
+			s.log.Error("failed to link song to album", sl.Err(err))
+			return 0, err
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		s.log.Error("failed to commit transaction", sl.Err(err))
 		return 0, err
@@ -110,29 +194,97 @@ func (s *LibraryService) SaveSong(ctx context.Context, model dto.SongRequest, re
 	return id, nil
 }
 
-func (s *LibraryService) GetLibrary(ctx context.Context, filters dto.Filters, limit int, offset int, requestID string) ([]models.Song, error) {
+// GetLibrary returns a page of songs by the offset or by a cursor from an
+// earlier page, which has to be made for the same order and filters. The page
+// has a cursor to the next page when there are more songs and one to the
+// previous page unless it is the first. With a count the page has the total of
+// the listing and with facets their counts, read in the same snapshot as the
+// songs so all of them agree.
+func (s *LibraryService) GetLibrary(ctx context.Context, filters dto.Filters, sort []dto.SortKey, limit int, offset int, pageCursor string, count string, facets []string, requestID string) (models.LibraryPage, error) {
 	const op = "library.service.GetLibrary"
 
 	s.log = with.WithOpAndRequestID(s.log, op, requestID)
 
-	tx, err := s.pool.Begin(ctx)
+	if filters.Fuzzy != nil && filters.FuzzyThreshold == nil {
+		filters.FuzzyThreshold = s.search.FuzzyThreshold
+	}
+
+	sortStr := dto.SortString(dto.Order(sort, filters))
+	filtersKey := dto.FiltersKey(filters)
+
+	var keyset *dto.Keyset
+	if pageCursor != "" {
+		c, err := s.cursors.DecodeFor(pageCursor, sortStr, filtersKey)
+		if err != nil {
+			s.log.Error("failed to decode cursor", sl.Err(err))
+			return models.LibraryPage{}, err
+		}
+		keyset = &dto.Keyset{Values: c.Values, Before: c.Before}
+	}
+	backward := keyset != nil && keyset.Before
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
 	if err != nil {
 		s.log.Error("failed to begin transaction", sl.Err(err))
-		return nil, err
+		return models.LibraryPage{}, err
 	}
 	defer tx.Rollback(ctx)
 
-	songs, err := s.db.GetLibray(ctx, tx, filters, limit, offset, requestID)
+	songs, err := s.db.GetLibray(ctx, tx, filters, sort, limit+1, offset, keyset, requestID)
 	if err != nil {
 		s.log.Error("failed to get library", sl.Err(err))
-		return nil, err
+		return models.LibraryPage{}, err
+	}
+
+	more := len(songs) > limit
+	if more && backward {
+		songs = songs[1:]
+	} else if more {
+		songs = songs[:limit]
+	}
+
+	page := models.LibraryPage{Items: songs, Limit: limit}
+	if page.Items == nil {
+		page.Items = []models.Song{}
+	}
+	if keyset == nil {
+		page.Offset = &offset
+	}
+	if len(songs) > 0 {
+		if more || backward {
+			page.NextCursor = s.cursors.Encode(cursor.Cursor{Sort: sortStr, Filters: filtersKey, Values: songs[len(songs)-1].SortValues})
+		}
+		if more && backward || !backward && (keyset != nil || offset > 0) {
+			page.PrevCursor = s.cursors.Encode(cursor.Cursor{Sort: sortStr, Filters: filtersKey, Values: songs[0].SortValues, Before: true})
+		}
+	}
+	page.HasMore = page.NextCursor != ""
+
+	if count != "" {
+		page.Total, err = s.db.CountLibrary(ctx, tx, filters, count == dto.CountEstimate, requestID)
+		if err != nil {
+			s.log.Error("failed to count library", sl.Err(err))
+			return models.LibraryPage{}, err
+		}
+	}
+
+	if len(facets) > 0 {
+		page.Facets, err = s.db.GetLibraryFacets(ctx, tx, filters, facets, requestID)
+		if err != nil {
+			s.log.Error("failed to get library facets", sl.Err(err))
+			return models.LibraryPage{}, err
+		}
 	}
 
 	s.log.Info("library successfully fetched", slog.Int("songs_count", len(songs)))
-	return songs, nil
+	return page, nil
 }
 
-func (s *LibraryService) GetSongText(ctx context.Context, songID int, couplet int, requestID string) (string, error) {
+// GetSongText returns a section of the lyrics in the requested language, an
+// empty lang or a language without a translation gives the original lyrics.
+// With a section type the index counts the sections of that type, without it
+// the index is the position of the section and 0 gives the whole text.
+func (s *LibraryService) GetSongText(ctx context.Context, songID int, section string, index int, lang string, requestID string) (sections.Section, string, error) {
 	const op = "library.service.GetSongText"
 
 	s.log = with.WithOpAndRequestID(s.log, op, requestID)
@@ -140,37 +292,56 @@ func (s *LibraryService) GetSongText(ctx context.Context, songID int, couplet in
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		s.log.Error("failed to begin transaction", sl.Err(err))
-		return "", err
+		return sections.Section{}, "", err
 	}
 	defer tx.Rollback(ctx)
 
-	text, err := s.db.GetSongText(ctx, tx, songID, requestID)
+	text, textLang, err := s.db.GetSongText(ctx, tx, songID, lang, requestID)
 	if err != nil {
 		s.log.Error("failed to get song text", sl.Err(err))
-		return "", err
-	}
-
-	cup := []rune{}
-	counter := 1
-Loop:
-	for _, symb := range text {
-		if symb == '\n' && len(cup) != 0 && cup[len(cup)-1] == '\n' {
-			if counter == couplet {
-				break Loop
-			}
-			cup = cup[:0]
-			counter++
-			continue Loop
-		} else {
-			cup = append(cup, symb)
-		}
+		return sections.Section{}, "", err
+	}
+
+	if section == "" && index == 0 {
+		s.log.Info("song text successfully fetched", slog.Int("song_id", songID), slog.String("lang", textLang))
+		return sections.Section{Text: text}, textLang, nil
+	}
+
+	secs, err := s.db.GetSongSections(ctx, tx, songID, textLang, requestID)
+	if err != nil {
+		s.log.Error("failed to get song sections", sl.Err(err))
+		return sections.Section{}, "", err
 	}
+	if len(secs) == 0 {
+		// lyrics saved before sections were stored
+		secs = sections.Parse(text)
+	}
+
+	sec, err := selectSection(secs, section, index)
+	if err != nil {
+		s.log.Error("failed to select section", sl.Err(err))
+		return sections.Section{}, "", err
+	}
+
+	s.log.Info("song text successfully fetched", slog.Int("song_id", songID), slog.String("section", sec.Label), slog.String("lang", textLang))
+	return sec, textLang, nil
+}
 
-	s.log.Info("song text successfully fetched", slog.Int("song_id", songID), slog.Int("couplet", couplet))
-	return string(cup), nil
+// selectSection picks the section by type and index or, without a type, by
+// position. The original lyrics and the translations are parsed the same way
+// so the sections line up between languages.
+func selectSection(secs []sections.Section, typ string, index int) (sections.Section, error) {
+	for _, sec := range secs {
+		if typ == "" && sec.Position == index || typ != "" && sec.Type == typ && sec.Index == index {
+			return sec, nil
+		}
+	}
+	return sections.Section{}, errors.New("section not found")
 }
 
-func (s *LibraryService) DeleteSong(ctx context.Context, songID int, requestID string) error {
+// DeleteSong moves the song to the trash, with purge the song is removed from
+// playlists and deleted for good.
+func (s *LibraryService) DeleteSong(ctx context.Context, songID int, purge bool, requestID string) error {
 	const op = "library.service.DeleteSong"
 
 	s.log = with.WithOpAndRequestID(s.log, op, requestID)
@@ -182,7 +353,12 @@ func (s *LibraryService) DeleteSong(ctx context.Context, songID int, requestID s
 	}
 	defer tx.Rollback(ctx)
 
-	err = s.db.DeleteSong(ctx, tx, songID, requestID)
+	var blobKeys []string
+	if purge {
+		blobKeys, err = s.purgeSong(ctx, tx, songID, requestID)
+	} else {
+		err = s.db.DeleteSong(ctx, tx, songID, requestID)
+	}
 	if err != nil {
 		s.log.Error("failed to delete song", sl.Err(err))
 		return err
@@ -193,6 +369,8 @@ func (s *LibraryService) DeleteSong(ctx context.Context, songID int, requestID s
 		return err
 	}
 
+	s.deleteBlobs(ctx, blobKeys)
+
 	s.log.Info("song was successfully deleted")
 	return nil
 }
@@ -0,0 +1,91 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+)
+
+func (s *LibraryService) GetSongLinks(ctx context.Context, songID int, requestID string) ([]models.Link, error) {
+	const op = "library.service.GetSongLinks"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	links, err := s.db.GetSongLinks(ctx, tx, songID, requestID)
+	if err != nil {
+		s.log.Error("failed to get song links", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("song links successfully fetched", slog.Int("links_count", len(links)))
+	return links, nil
+}
+
+func (s *LibraryService) AddSongLink(ctx context.Context, songID int, link dto.Link, requestID string) (int, error) {
+	const op = "library.service.AddSongLink"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	linkDB, err := link.ToDBModel()
+	if err != nil {
+		s.log.Error("failed to convert link to db model", sl.Err(err))
+		return 0, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := s.db.AddSongLink(ctx, tx, songID, linkDB, requestID)
+	if err != nil {
+		s.log.Error("failed to add song link", sl.Err(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return 0, err
+	}
+
+	s.log.Info("song link was successfully added", slog.Int("id", id))
+	return id, nil
+}
+
+func (s *LibraryService) RemoveSongLink(ctx context.Context, songID int, linkID int, requestID string) error {
+	const op = "library.service.RemoveSongLink"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.RemoveSongLink(ctx, tx, songID, linkID, requestID); err != nil {
+		s.log.Error("failed to remove song link", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("song link was successfully removed")
+	return nil
+}
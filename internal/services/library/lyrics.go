@@ -0,0 +1,174 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/lrc"
+)
+
+func (s *LibraryService) GetLyrics(ctx context.Context, songID int, requestID string) ([]models.Lyrics, error) {
+	const op = "library.service.GetLyrics"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	lyrics, err := s.db.GetLyrics(ctx, tx, songID, requestID)
+	if err != nil {
+		s.log.Error("failed to get lyrics", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("lyrics successfully fetched", slog.Int("lyrics_count", len(lyrics)))
+	return lyrics, nil
+}
+
+// SaveLyrics saves the translation in the given language. Original lyrics
+// replace the song text, which records a revision, and take the language over.
+func (s *LibraryService) SaveLyrics(ctx context.Context, songID int, lang string, lyrics dto.Lyrics, requestID string) error {
+	const op = "library.service.SaveLyrics"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if lyrics.Original {
+		if err := s.db.UpdateSong(ctx, tx, dto.UpdateSong{ID: songID, Text: lyrics.Text}, requestID); err != nil {
+			s.log.Error("failed to update song text", sl.Err(err))
+			return err
+		}
+		if err := s.db.SetOriginalLang(ctx, tx, songID, lang, requestID); err != nil {
+			s.log.Error("failed to set original lang", sl.Err(err))
+			return err
+		}
+	} else {
+		if err := s.db.SaveTranslation(ctx, tx, songID, lang, lyrics.Text, requestID); err != nil {
+			s.log.Error("failed to save translation", sl.Err(err))
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("lyrics were successfully saved", slog.String("lang", lang), slog.Bool("original", lyrics.Original))
+	return nil
+}
+
+func (s *LibraryService) DeleteTranslation(ctx context.Context, songID int, lang string, requestID string) error {
+	const op = "library.service.DeleteTranslation"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.DeleteTranslation(ctx, tx, songID, lang, requestID); err != nil {
+		s.log.Error("failed to delete translation", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("translation was successfully deleted")
+	return nil
+}
+
+// GetSyncedLyrics returns the timed lines with the language they are in, see
+// GetSongText for how the language is picked.
+func (s *LibraryService) GetSyncedLyrics(ctx context.Context, songID int, lang string, requestID string) ([]lrc.Line, string, error) {
+	const op = "library.service.GetSyncedLyrics"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, "", err
+	}
+	defer tx.Rollback(ctx)
+
+	lines, syncedLang, err := s.db.GetSyncedLyrics(ctx, tx, songID, lang, requestID)
+	if err != nil {
+		s.log.Error("failed to get synced lyrics", sl.Err(err))
+		return nil, "", err
+	}
+
+	s.log.Info("synced lyrics successfully fetched", slog.Int("lines_count", len(lines)))
+	return lines, syncedLang, nil
+}
+
+func (s *LibraryService) SaveSyncedLyrics(ctx context.Context, songID int, lang string, lines []lrc.Line, requestID string) error {
+	const op = "library.service.SaveSyncedLyrics"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.SaveSyncedLyrics(ctx, tx, songID, lang, lines, requestID); err != nil {
+		s.log.Error("failed to save synced lyrics", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("synced lyrics were successfully saved", slog.String("lang", lang), slog.Int("lines_count", len(lines)))
+	return nil
+}
+
+func (s *LibraryService) DeleteSyncedLyrics(ctx context.Context, songID int, lang string, requestID string) error {
+	const op = "library.service.DeleteSyncedLyrics"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.DeleteSyncedLyrics(ctx, tx, songID, lang, requestID); err != nil {
+		s.log.Error("failed to delete synced lyrics", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("synced lyrics were successfully deleted")
+	return nil
+}
@@ -0,0 +1,107 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+)
+
+func (s *LibraryService) GetPeople(ctx context.Context, limit int, offset int, requestID string) ([]models.Person, error) {
+	const op = "library.service.GetPeople"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	people, err := s.db.GetPeople(ctx, tx, limit, offset, requestID)
+	if err != nil {
+		s.log.Error("failed to get people", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("people successfully fetched", slog.Int("people_count", len(people)))
+	return people, nil
+}
+
+func (s *LibraryService) GetPersonSongs(ctx context.Context, personID int, limit int, offset int, requestID string) ([]models.PersonSong, error) {
+	const op = "library.service.GetPersonSongs"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	songs, err := s.db.GetPersonSongs(ctx, tx, personID, limit, offset, requestID)
+	if err != nil {
+		s.log.Error("failed to get person songs", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("person songs successfully fetched", slog.Int("songs_count", len(songs)))
+	return songs, nil
+}
+
+func (s *LibraryService) AddPersonCredit(ctx context.Context, songID int, credit dto.PersonCredit, requestID string) (int, error) {
+	const op = "library.service.AddPersonCredit"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	personID, err := s.db.AddPersonCredit(ctx, tx, songID, credit, requestID)
+	if err != nil {
+		s.log.Error("failed to add person credit", sl.Err(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return 0, err
+	}
+
+	s.log.Info("person credit was successfully added", slog.Int("person_id", personID))
+	return personID, nil
+}
+
+func (s *LibraryService) RemovePersonCredit(ctx context.Context, songID int, personID int, role string, requestID string) error {
+	const op = "library.service.RemovePersonCredit"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.RemovePersonCredit(ctx, tx, songID, personID, role, requestID); err != nil {
+		s.log.Error("failed to remove person credit", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("person credit was successfully removed")
+	return nil
+}
@@ -0,0 +1,213 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+)
+
+func (s *LibraryService) SavePlaylist(ctx context.Context, model dto.Playlist, requestID string) (int, error) {
+	const op = "library.service.SavePlaylist"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	id, err := s.db.SavePlaylist(ctx, tx, model, requestID)
+	if err != nil {
+		s.log.Error("failed to save playlist", sl.Err(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return 0, err
+	}
+
+	s.log.Info("playlist was successfully saved", slog.Int("id", id))
+	return id, nil
+}
+
+func (s *LibraryService) GetPlaylists(ctx context.Context, limit int, offset int, requestID string) ([]models.Playlist, error) {
+	const op = "library.service.GetPlaylists"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	playlists, err := s.db.GetPlaylists(ctx, tx, limit, offset, requestID)
+	if err != nil {
+		s.log.Error("failed to get playlists", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("playlists successfully fetched", slog.Int("playlists_count", len(playlists)))
+	return playlists, nil
+}
+
+func (s *LibraryService) GetPlaylist(ctx context.Context, playlistID int, requestID string) (models.Playlist, error) {
+	const op = "library.service.GetPlaylist"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return models.Playlist{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	playlist, err := s.db.GetPlaylist(ctx, tx, playlistID, requestID)
+	if err != nil {
+		s.log.Error("failed to get playlist", sl.Err(err))
+		return models.Playlist{}, err
+	}
+
+	s.log.Info("playlist successfully fetched", slog.Int("playlist_id", playlistID))
+	return playlist, nil
+}
+
+func (s *LibraryService) UpdatePlaylist(ctx context.Context, updateModel dto.UpdatePlaylist, requestID string) error {
+	const op = "library.service.UpdatePlaylist"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.UpdatePlaylist(ctx, tx, updateModel, requestID); err != nil {
+		s.log.Error("failed to update playlist", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("playlist was successfully updated")
+	return nil
+}
+
+func (s *LibraryService) DeletePlaylist(ctx context.Context, playlistID int, requestID string) error {
+	const op = "library.service.DeletePlaylist"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.DeletePlaylist(ctx, tx, playlistID, requestID); err != nil {
+		s.log.Error("failed to delete playlist", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("playlist was successfully deleted")
+	return nil
+}
+
+func (s *LibraryService) AddPlaylistEntry(ctx context.Context, playlistID int, entry dto.AddPlaylistEntry, requestID string) (int, int, error) {
+	const op = "library.service.AddPlaylistEntry"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	id, position, err := s.db.AddPlaylistEntry(ctx, tx, playlistID, entry, requestID)
+	if err != nil {
+		s.log.Error("failed to add playlist entry", sl.Err(err))
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return 0, 0, err
+	}
+
+	s.log.Info("playlist entry was successfully added", slog.Int("id", id), slog.Int("position", position))
+	return id, position, nil
+}
+
+func (s *LibraryService) RemovePlaylistEntry(ctx context.Context, playlistID int, entryID int, requestID string) error {
+	const op = "library.service.RemovePlaylistEntry"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.RemovePlaylistEntry(ctx, tx, playlistID, entryID, requestID); err != nil {
+		s.log.Error("failed to remove playlist entry", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("playlist entry was successfully removed")
+	return nil
+}
+
+func (s *LibraryService) MovePlaylistEntry(ctx context.Context, playlistID int, entryID int, move dto.MovePlaylistEntry, requestID string) (int, error) {
+	const op = "library.service.MovePlaylistEntry"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	position, err := s.db.MovePlaylistEntry(ctx, tx, playlistID, entryID, move.Position, requestID)
+	if err != nil {
+		s.log.Error("failed to move playlist entry", sl.Err(err))
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return 0, err
+	}
+
+	s.log.Info("playlist entry was successfully moved", slog.Int("position", position))
+	return position, nil
+}
@@ -0,0 +1,84 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+)
+
+func (s *LibraryService) LinkSongs(ctx context.Context, songID int, relation dto.SongRelation, requestID string) error {
+	const op = "library.service.LinkSongs"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.LinkSongs(ctx, tx, songID, relation, requestID); err != nil {
+		s.log.Error("failed to link songs", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("songs were successfully linked", slog.String("relation", relation.Relation))
+	return nil
+}
+
+func (s *LibraryService) UnlinkSongs(ctx context.Context, songID int, originalID int, requestID string) error {
+	const op = "library.service.UnlinkSongs"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.UnlinkSongs(ctx, tx, songID, originalID, requestID); err != nil {
+		s.log.Error("failed to unlink songs", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("songs were successfully unlinked")
+	return nil
+}
+
+func (s *LibraryService) GetSongVersions(ctx context.Context, songID int, requestID string) (models.SongVersions, error) {
+	const op = "library.service.GetSongVersions"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return models.SongVersions{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	versions, err := s.db.GetSongVersions(ctx, tx, songID, requestID)
+	if err != nil {
+		s.log.Error("failed to get song versions", sl.Err(err))
+		return models.SongVersions{}, err
+	}
+
+	s.log.Info("song versions successfully fetched", slog.Int("songs_count", len(versions.Songs)))
+	return versions, nil
+}
@@ -0,0 +1,145 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/diff"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/partialdate"
+)
+
+func (s *LibraryService) GetSongRevisions(ctx context.Context, songID int, requestID string) ([]models.SongRevision, error) {
+	const op = "library.service.GetSongRevisions"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	revisions, err := s.db.GetSongRevisions(ctx, tx, songID, requestID)
+	if err != nil {
+		s.log.Error("failed to get song revisions", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("song revisions successfully fetched", slog.Int("revisions_count", len(revisions)))
+	return revisions, nil
+}
+
+func (s *LibraryService) GetSongTextDiff(ctx context.Context, songID int, from int, to int, requestID string) ([]diff.Line, error) {
+	const op = "library.service.GetSongTextDiff"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	fromRev, err := s.db.GetSongRevision(ctx, tx, songID, from, requestID)
+	if err != nil {
+		s.log.Error("failed to get song revision", sl.Err(err))
+		return nil, err
+	}
+
+	toRev, err := s.db.GetSongRevision(ctx, tx, songID, to, requestID)
+	if err != nil {
+		s.log.Error("failed to get song revision", sl.Err(err))
+		return nil, err
+	}
+
+	lines, err := diff.Lines(fromRev.Song.Text, toRev.Song.Text)
+	if err != nil {
+		s.log.Error("failed to diff song text", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("song text diff successfully built", slog.Int("from", from), slog.Int("to", to))
+	return lines, nil
+}
+
+// RestoreSongRevision brings every field of the song back to the given
+// revision, the restore itself is recorded as a new revision. A song in the
+// trash has to be restored from there first.
+func (s *LibraryService) RestoreSongRevision(ctx context.Context, songID int, revision int, requestID string) error {
+	const op = "library.service.RestoreSongRevision"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rev, err := s.db.GetSongRevision(ctx, tx, songID, revision, requestID)
+	if err != nil {
+		s.log.Error("failed to get song revision", sl.Err(err))
+		return err
+	}
+
+	updateModel, err := restoreModel(songID, rev.Song)
+	if err != nil {
+		s.log.Error("failed to convert revision to update model", sl.Err(err))
+		return err
+	}
+
+	if err := s.db.UpdateSong(ctx, tx, updateModel, requestID); err != nil {
+		s.log.Error("failed to update song", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("song revision was successfully restored", slog.Int("revision", revision))
+	return nil
+}
+
+// restoreModel converts the state to the update model with the values already
+// in the form UpdateSong.Validate leaves them, empty metadata is passed as
+// typed nil pointers so the columns are reset to NULL.
+func restoreModel(songID int, state models.SongState) (dto.UpdateSong, error) {
+	releaseDate, err := partialdate.Parse(state.ReleaseDate)
+	if err != nil {
+		return dto.UpdateSong{}, err
+	}
+
+	updateModel := dto.UpdateSong{
+		ID:          songID,
+		Group:       state.Group,
+		Song:        state.Song,
+		ReleaseDate: releaseDate,
+		Text:        state.Text,
+		Duration:    state.Duration,
+		BPM:         state.BPM,
+		Key:         state.Key,
+		ISRC:        state.ISRC,
+		Explicit:    state.Explicit,
+	}
+
+	// a revision without a primary link clears the current one
+	link := dto.LinkDB{}
+	if state.Patronymic != "" {
+		link, err = dto.ParseLink(state.Patronymic)
+		if err != nil {
+			return dto.UpdateSong{}, err
+		}
+		link.Primary = true
+	}
+	updateModel.Patronymic = link
+
+	return updateModel, nil
+}
@@ -0,0 +1,113 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+)
+
+func (s *LibraryService) AddSongTags(ctx context.Context, songID int, tags dto.SongTags, requestID string) error {
+	const op = "library.service.AddSongTags"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.AddSongTags(ctx, tx, songID, tags.Tags, requestID); err != nil {
+		s.log.Error("failed to add song tags", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("song tags were successfully added", slog.Int("song_id", songID))
+	return nil
+}
+
+func (s *LibraryService) RemoveSongTag(ctx context.Context, songID int, tag string, requestID string) error {
+	const op = "library.service.RemoveSongTag"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.RemoveSongTag(ctx, tx, songID, tag, requestID); err != nil {
+		s.log.Error("failed to remove song tag", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("song tag was successfully removed", slog.Int("song_id", songID))
+	return nil
+}
+
+func (s *LibraryService) AddSongGenres(ctx context.Context, songID int, genres dto.SongGenres, requestID string) error {
+	const op = "library.service.AddSongGenres"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.AddSongGenres(ctx, tx, songID, genres.Genres, requestID); err != nil {
+		s.log.Error("failed to add song genres", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("song genres were successfully added", slog.Int("song_id", songID))
+	return nil
+}
+
+func (s *LibraryService) RemoveSongGenre(ctx context.Context, songID int, genre string, requestID string) error {
+	const op = "library.service.RemoveSongGenre"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.RemoveSongGenre(ctx, tx, songID, genre, requestID); err != nil {
+		s.log.Error("failed to remove song genre", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("song genre was successfully removed", slog.Int("song_id", songID))
+	return nil
+}
@@ -0,0 +1,141 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/config"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *LibraryService) GetTrash(ctx context.Context, limit int, offset int, requestID string) ([]models.TrashItem, error) {
+	const op = "library.service.GetTrash"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	items, err := s.db.GetTrash(ctx, tx, limit, offset, requestID)
+	if err != nil {
+		s.log.Error("failed to get trash", sl.Err(err))
+		return nil, err
+	}
+
+	s.log.Info("trash successfully fetched", slog.Int("songs_count", len(items)))
+	return items, nil
+}
+
+func (s *LibraryService) RestoreSong(ctx context.Context, songID int, requestID string) error {
+	const op = "library.service.RestoreSong"
+
+	s.log = with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		s.log.Error("failed to begin transaction", sl.Err(err))
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := s.db.RestoreSong(ctx, tx, songID, requestID); err != nil {
+		s.log.Error("failed to restore song", sl.Err(err))
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.log.Error("failed to commit transaction", sl.Err(err))
+		return err
+	}
+
+	s.log.Info("song was successfully restored")
+	return nil
+}
+
+// PurgeTrash deletes for good the songs which have been in the trash longer
+// than the retention and returns how many of them were removed.
+func (s *LibraryService) PurgeTrash(ctx context.Context, retention time.Duration, requestID string) (int, error) {
+	const op = "library.service.PurgeTrash"
+
+	// the purge runs in the background next to requests, so it keeps its
+	// logger to itself rather than replacing the shared one
+	log := with.WithOpAndRequestID(s.log, op, requestID)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", sl.Err(err))
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	ids, err := s.db.GetExpiredTrash(ctx, tx, time.Now().Add(-retention), requestID)
+	if err != nil {
+		log.Error("failed to get expired trash", sl.Err(err))
+		return 0, err
+	}
+
+	var blobKeys []string
+	for _, id := range ids {
+		keys, err := s.purgeSong(ctx, tx, id, requestID)
+		if err != nil {
+			log.Error("failed to purge song", slog.Int("song_id", id), sl.Err(err))
+			return 0, err
+		}
+		blobKeys = append(blobKeys, keys...)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("failed to commit transaction", sl.Err(err))
+		return 0, err
+	}
+
+	s.deleteBlobs(ctx, blobKeys)
+
+	log.Info("trash was successfully purged", slog.Int("songs_count", len(ids)))
+	return len(ids), nil
+}
+
+// RunTrashPurge purges the expired trash every purge interval until the
+// context is cancelled.
+func (s *LibraryService) RunTrashPurge(ctx context.Context, cfg config.Trash) {
+	const op = "library.service.RunTrashPurge"
+
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PurgeTrash(ctx, cfg.Retention, ""); err != nil && ctx.Err() == nil {
+			s.log.Error("failed to purge trash", slog.String("op", op), sl.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeSong deletes the song for good and returns the blob keys of its
+// attachments, they are removed with deleteBlobs after the commit.
+func (s *LibraryService) purgeSong(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]string, error) {
+	keys, err := s.db.GetSongBlobKeys(ctx, tx, songID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.RemoveSongFromPlaylists(ctx, tx, songID, requestID); err != nil {
+		return nil, err
+	}
+	if err := s.db.PurgeSong(ctx, tx, songID, requestID); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,34 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"music-library/internal/config"
+)
+
+var (
+	ErrNotFound = errors.New("blob not found")
+	ErrTooLarge = errors.New("blob is too large")
+)
+
+// Store keeps binary data under keys like songs/1/3f2a, the keys are made by
+// the caller and never come from a request.
+type Store interface {
+	// Put writes the reader under the key and returns the number of bytes
+	// written. With a positive limit a larger blob is not kept and ErrTooLarge
+	// is returned.
+	Put(ctx context.Context, key string, r io.Reader, limit int64) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+func New(cfg config.Attachments) (Store, error) {
+	switch cfg.Store {
+	case "local":
+		return NewLocal(cfg.Dir)
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.Store)
+	}
+}
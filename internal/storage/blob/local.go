@@ -0,0 +1,110 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Local keeps blobs as files in a directory.
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+// Put writes into a temporary file first so a reader never sees a partial
+// blob.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, limit int64) (int64, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	src := r
+	if limit > 0 {
+		src = io.LimitReader(r, limit+1)
+	}
+
+	n, err := io.Copy(tmp, src)
+	if err != nil {
+		return 0, err
+	}
+	if limit > 0 && n > limit {
+		return 0, ErrTooLarge
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (l *Local) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Delete removes the blob, a missing blob is not an error.
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
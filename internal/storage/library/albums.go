@@ -0,0 +1,412 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// albumReleaseDateColumn renders the release date of albums aliased as al up
+// to its precision, like releaseDateColumn does for songs.
+const albumReleaseDateColumn = `
+	CASE al.release_date_precision
+		WHEN 'year' THEN to_char(al.release_date, 'YYYY')
+		WHEN 'month' THEN to_char(al.release_date, 'MM.YYYY')
+		ELSE to_char(al.release_date, 'DD.MM.YYYY')
+	END`
+
+func (db *LibraryDB) SaveAlbum(ctx context.Context, tx pgx.Tx, model dto.AlbumDB, requestID string) (int, error) {
+	const op = "storage.library.SaveAlbum"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	artistID, err := db.getOrCreateArtist(ctx, tx, model.Artist)
+	if err != nil {
+		return 0, err
+	}
+
+	q := `
+		INSERT INTO albums
+		(title, artist_id, release_date, release_date_precision, album_type)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id;
+	`
+	db.log.Debug("save new album query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, model.Title, artistID, model.ReleaseDate.Time, model.ReleaseDate.Precision, model.Type).Scan(&id); err != nil {
+		if pgerrors.IsUniqueViolation(err) {
+			db.log.Error("album already exists", slog.String("title", model.Title))
+			return 0, errors.New("album already exists")
+		}
+		db.log.Error("failed to save a new album", sl.Err(err))
+		return 0, err
+	}
+
+	db.log.Info("new album was successfully saved", slog.Int("id", id))
+	return id, nil
+}
+
+func (db *LibraryDB) GetAlbums(ctx context.Context, tx pgx.Tx, artistID int, limit int, offset int, requestID string) ([]models.Album, error) {
+	const op = "storage.library.GetAlbums"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT al.id, al.title, al.artist_id, a.name, ` + albumReleaseDateColumn + `, al.release_date_precision, al.album_type
+		FROM albums al
+		JOIN artists a ON a.id = al.artist_id
+		WHERE $1 = 0 OR al.artist_id = $1
+		ORDER BY al.release_date, al.id
+		LIMIT $2
+		OFFSET $3;
+	`
+	db.log.Debug("get albums query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, artistID, limit, offset)
+	if err != nil {
+		db.log.Error("failed to get albums", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	albums := []models.Album{}
+	for rows.Next() {
+		var album models.Album
+		if err := rows.Scan(&album.ID, &album.Title, &album.ArtistID, &album.Artist, &album.ReleaseDate, &album.ReleaseDatePrecision, &album.Type); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		albums = append(albums, album)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("albums were successfully retrieved", slog.Int("count", len(albums)))
+	return albums, nil
+}
+
+func (db *LibraryDB) GetAlbum(ctx context.Context, tx pgx.Tx, albumID int, requestID string) (models.Album, error) {
+	const op = "storage.library.GetAlbum"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT al.id, al.title, al.artist_id, a.name, ` + albumReleaseDateColumn + `, al.release_date_precision, al.album_type
+		FROM albums al
+		JOIN artists a ON a.id = al.artist_id
+		WHERE al.id = $1;
+	`
+	db.log.Debug("get album query", slog.String("query", query.QueryToString(q)))
+
+	var album models.Album
+	if err := tx.QueryRow(ctx, q, albumID).Scan(&album.ID, &album.Title, &album.ArtistID, &album.Artist, &album.ReleaseDate, &album.ReleaseDatePrecision, &album.Type); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("album not found", slog.Int("album_id", albumID))
+			return models.Album{}, errors.New("album not found")
+		}
+		db.log.Error("failed to get album", sl.Err(err))
+		return models.Album{}, err
+	}
+
+	q = `
+		SELECT t.position, t.song_id, l.song
+		FROM album_tracks t
+		JOIN library l ON l.id = t.song_id
+		WHERE t.album_id = $1 AND l.deleted_at IS NULL
+		ORDER BY t.position;
+	`
+	db.log.Debug("get album tracks query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, albumID)
+	if err != nil {
+		db.log.Error("failed to get album tracks", sl.Err(err))
+		return models.Album{}, err
+	}
+	defer rows.Close()
+
+	album.Tracks = []models.AlbumTrack{}
+	for rows.Next() {
+		var track models.AlbumTrack
+		if err := rows.Scan(&track.Position, &track.SongID, &track.Song); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return models.Album{}, err
+		}
+		album.Tracks = append(album.Tracks, track)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return models.Album{}, err
+	}
+
+	db.log.Info("album was successfully retrieved", slog.Int("album_id", albumID))
+	return album, nil
+}
+
+func (db *LibraryDB) DeleteAlbum(ctx context.Context, tx pgx.Tx, albumID int, requestID string) error {
+	const op = "storage.library.DeleteAlbum"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		DELETE FROM albums
+		WHERE id = $1
+		RETURNING id;
+	`
+	db.log.Debug("delete album query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, albumID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("album not found", slog.Int("album_id", albumID))
+			return errors.New("album not found")
+		}
+		db.log.Error("failed to delete album", sl.Err(err))
+		return err
+	}
+
+	db.log.Info("album was successfully deleted", slog.Int("id", id))
+	return nil
+}
+
+func (db *LibraryDB) AddAlbumTrack(ctx context.Context, tx pgx.Tx, albumID int, track dto.AddAlbumTrack, requestID string) (int, error) {
+	const op = "storage.library.AddAlbumTrack"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, track.SongID); err != nil {
+		return 0, err
+	}
+
+	position, err := db.addAlbumTrack(ctx, tx, albumID, track.SongID, track.Position)
+	if err != nil {
+		return 0, err
+	}
+
+	db.log.Info("track was successfully added to album", slog.Int("album_id", albumID), slog.Int("position", position))
+	return position, nil
+}
+
+func (db *LibraryDB) ReorderAlbumTracks(ctx context.Context, tx pgx.Tx, albumID int, songIDs []int, requestID string) error {
+	const op = "storage.library.ReorderAlbumTracks"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.lockAlbum(ctx, tx, albumID); err != nil {
+		return err
+	}
+
+	q := `
+		SELECT COALESCE(array_agg(song_id), '{}')
+		FROM album_tracks
+		WHERE album_id = $1;
+	`
+	db.log.Debug("get album track ids query", slog.String("query", query.QueryToString(q)))
+
+	var current []int
+	if err := tx.QueryRow(ctx, q, albumID).Scan(&current); err != nil {
+		db.log.Error("failed to get album tracks", sl.Err(err))
+		return err
+	}
+
+	sorted := slices.Clone(songIDs)
+	slices.Sort(sorted)
+	slices.Sort(current)
+	if !slices.Equal(sorted, current) {
+		db.log.Error("reorder does not match album tracks", slog.Int("album_id", albumID))
+		return errors.New("song_ids must list every track of the album exactly once")
+	}
+
+	q = `
+		UPDATE album_tracks t
+		SET position = o.position
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(song_id, position)
+		WHERE t.album_id = $1 AND t.song_id = o.song_id;
+	`
+	db.log.Debug("reorder album tracks query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, albumID, songIDs); err != nil {
+		db.log.Error("failed to reorder album tracks", sl.Err(err))
+		return err
+	}
+
+	db.log.Info("album tracks were successfully reordered", slog.Int("album_id", albumID))
+	return nil
+}
+
+func (db *LibraryDB) RemoveAlbumTrack(ctx context.Context, tx pgx.Tx, albumID int, songID int, requestID string) error {
+	const op = "storage.library.RemoveAlbumTrack"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.lockAlbum(ctx, tx, albumID); err != nil {
+		return err
+	}
+
+	q := `
+		DELETE FROM album_tracks
+		WHERE album_id = $1 AND song_id = $2
+		RETURNING position;
+	`
+	db.log.Debug("remove album track query", slog.String("query", query.QueryToString(q)))
+
+	var position int
+	if err := tx.QueryRow(ctx, q, albumID, songID).Scan(&position); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("track not found", slog.Int("album_id", albumID), slog.Int("song_id", songID))
+			return errors.New("song is not on this album")
+		}
+		db.log.Error("failed to remove album track", sl.Err(err))
+		return err
+	}
+
+	q = `
+		UPDATE album_tracks
+		SET position = position - 1
+		WHERE album_id = $1 AND position > $2;
+	`
+	db.log.Debug("shift album tracks query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, albumID, position); err != nil {
+		db.log.Error("failed to shift album tracks", sl.Err(err))
+		return err
+	}
+
+	db.log.Info("track was successfully removed from album", slog.Int("album_id", albumID), slog.Int("song_id", songID))
+	return nil
+}
+
+// SaveSongAlbum links a freshly saved song to the album described by the /info
+// response, creating the album for the song's artist when it is not known yet.
+// An empty type leaves it unknown, a known album only gets a type it lacks.
+func (db *LibraryDB) SaveSongAlbum(ctx context.Context, tx pgx.Tx, songID int, album dto.AlbumDB, requestID string) (int, error) {
+	const op = "storage.library.SaveSongAlbum"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		INSERT INTO albums
+		(title, artist_id, release_date, release_date_precision, album_type)
+		SELECT $1, l.artist_id, $2, $3, NULLIF($4, '')
+		FROM library l
+		WHERE l.id = $5
+		ON CONFLICT (artist_id, LOWER(title)) DO UPDATE SET album_type = COALESCE(albums.album_type, EXCLUDED.album_type)
+		RETURNING id;
+	`
+	db.log.Debug("get or create song album query", slog.String("query", query.QueryToString(q)))
+
+	var albumID int
+	if err := tx.QueryRow(ctx, q, album.Title, album.ReleaseDate.Time, album.ReleaseDate.Precision, album.Type, songID).Scan(&albumID); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return 0, errors.New("song not found")
+		}
+		db.log.Error("failed to get or create album", sl.Err(err))
+		return 0, err
+	}
+
+	position, err := db.addAlbumTrack(ctx, tx, albumID, songID, album.Track)
+	if err != nil {
+		return 0, err
+	}
+
+	db.log.Info("song was successfully linked to album", slog.Int("album_id", albumID), slog.Int("position", position))
+	return albumID, nil
+}
+
+// addAlbumTrack puts the song at the given position, shifting the following
+// tracks down when the position is taken. Position 0 or one past the end
+// appends the song.
+func (db *LibraryDB) addAlbumTrack(ctx context.Context, tx pgx.Tx, albumID int, songID int, position int) (int, error) {
+	if err := db.lockAlbum(ctx, tx, albumID); err != nil {
+		return 0, err
+	}
+
+	q := `
+		SELECT COALESCE(MAX(position), 0) + 1
+		FROM album_tracks
+		WHERE album_id = $1;
+	`
+	db.log.Debug("get next album position query", slog.String("query", query.QueryToString(q)))
+
+	var next int
+	if err := tx.QueryRow(ctx, q, albumID).Scan(&next); err != nil {
+		db.log.Error("failed to get next album position", sl.Err(err))
+		return 0, err
+	}
+
+	if position <= 0 || position > next {
+		position = next
+	} else {
+		q := `
+			UPDATE album_tracks
+			SET position = position + 1
+			WHERE album_id = $1 AND position >= $2
+			AND EXISTS (SELECT 1 FROM album_tracks WHERE album_id = $1 AND position = $2);
+		`
+		db.log.Debug("shift album tracks query", slog.String("query", query.QueryToString(q)))
+
+		if _, err := tx.Exec(ctx, q, albumID, position); err != nil {
+			db.log.Error("failed to shift album tracks", sl.Err(err))
+			return 0, err
+		}
+	}
+
+	q = `
+		INSERT INTO album_tracks
+		(album_id, song_id, position)
+		VALUES ($1, $2, $3);
+	`
+	db.log.Debug("add album track query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, albumID, songID, position); err != nil {
+		if pgerrors.IsUniqueViolation(err) {
+			db.log.Error("song is already on this album", slog.Int("album_id", albumID), slog.Int("song_id", songID))
+			return 0, errors.New("song is already on this album")
+		}
+		if pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return 0, errors.New("song not found")
+		}
+		db.log.Error("failed to add album track", sl.Err(err))
+		return 0, err
+	}
+
+	return position, nil
+}
+
+// lockAlbum serializes track list changes of one album.
+func (db *LibraryDB) lockAlbum(ctx context.Context, tx pgx.Tx, albumID int) error {
+	q := `
+		SELECT id
+		FROM albums
+		WHERE id = $1
+		FOR UPDATE;
+	`
+	db.log.Debug("lock album query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, albumID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("album not found", slog.Int("album_id", albumID))
+			return errors.New("album not found")
+		}
+		db.log.Error("failed to lock album", sl.Err(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+	"music-library/internal/lib/storage/tools"
+	"music-library/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *LibraryDB) SaveArtist(ctx context.Context, tx pgx.Tx, model dto.Artist, requestID string) (int, error) {
+	const op = "storage.library.SaveArtist"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		INSERT INTO artists
+		(name, sort_name, country, formed_year)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id;
+	`
+	db.log.Debug("save new artist query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, model.Name, model.SortName, model.Country, model.FormedYear).Scan(&id); err != nil {
+		if pgerrors.IsUniqueViolation(err) {
+			db.log.Error("artist already exists", slog.String("name", model.Name))
+			return 0, errors.New("artist already exists")
+		}
+		db.log.Error("failed to save a new artist", sl.Err(err))
+		return 0, err
+	}
+
+	db.log.Info("new artist was successfully saved", slog.Int("id", id))
+	return id, nil
+}
+
+func (db *LibraryDB) GetArtists(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.Artist, error) {
+	const op = "storage.library.GetArtists"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT id, name, sort_name, country, formed_year
+		FROM artists
+		ORDER BY LOWER(COALESCE(sort_name, name)), id
+		LIMIT $1
+		OFFSET $2;
+	`
+	db.log.Debug("get artists query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, limit, offset)
+	if err != nil {
+		db.log.Error("failed to get artists", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	artists := []models.Artist{}
+	for rows.Next() {
+		var artist models.Artist
+		if err := rows.Scan(&artist.ID, &artist.Name, &artist.SortName, &artist.Country, &artist.FormedYear); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		artists = append(artists, artist)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("artists were successfully retrieved", slog.Int("count", len(artists)))
+	return artists, nil
+}
+
+func (db *LibraryDB) GetArtist(ctx context.Context, tx pgx.Tx, artistID int, requestID string) (models.Artist, error) {
+	const op = "storage.library.GetArtist"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT id, name, sort_name, country, formed_year
+		FROM artists
+		WHERE id = $1;
+	`
+	db.log.Debug("get artist query", slog.String("query", query.QueryToString(q)))
+
+	var artist models.Artist
+	if err := tx.QueryRow(ctx, q, artistID).Scan(&artist.ID, &artist.Name, &artist.SortName, &artist.Country, &artist.FormedYear); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("artist not found", slog.Int("artist_id", artistID))
+			return models.Artist{}, errors.New("artist not found")
+		}
+		db.log.Error("failed to get artist", sl.Err(err))
+		return models.Artist{}, err
+	}
+
+	db.log.Info("artist was successfully retrieved", slog.Int("artist_id", artistID))
+	return artist, nil
+}
+
+func (db *LibraryDB) UpdateArtist(ctx context.Context, tx pgx.Tx, updateModel dto.UpdateArtist, requestID string) error {
+	const op = "storage.library.UpdateArtist"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+	strParams, params := tools.GetArtistUpdateParams(updateModel)
+
+	q := fmt.Sprintf(`
+		UPDATE artists
+		SET %s
+		WHERE id = $%d
+		RETURNING id;
+	`, strParams, len(params)+1)
+
+	params = append(params, updateModel.ID)
+
+	db.log.Debug("update artist query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, params...).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("artist not found", slog.Int("artist_id", updateModel.ID))
+			return errors.New("artist not found")
+		}
+		if pgerrors.IsUniqueViolation(err) {
+			db.log.Error("artist with this name already exists", slog.Int("artist_id", updateModel.ID))
+			return errors.New("artist with this name already exists")
+		}
+		db.log.Error("failed to update artist", sl.Err(err))
+		return err
+	}
+
+	if updateModel.Name != nil {
+		q = `
+			UPDATE library l
+			SET dedup_key = normalize_key(a.name) || E'\n' || normalize_key(l.song)
+			FROM artists a
+			WHERE a.id = l.artist_id AND l.artist_id = $1;
+		`
+		db.log.Debug("refresh artist dedup keys query", slog.String("query", query.QueryToString(q)))
+
+		if _, err := tx.Exec(ctx, q, id); err != nil {
+			db.log.Error("failed to refresh artist dedup keys", sl.Err(err))
+			return err
+		}
+
+		// names that differ only in case, accents or spaces give the songs of
+		// another artist's keys, the keys are locked in order like a single
+		// song's key in checkDuplicate
+		q = `
+			SELECT pg_advisory_xact_lock(hashtext(k.dedup_key))
+			FROM (
+				SELECT DISTINCT dedup_key
+				FROM library
+				WHERE artist_id = $1 AND deleted_at IS NULL
+				ORDER BY dedup_key
+			) k;
+		`
+		db.log.Debug("lock artist dedup keys query", slog.String("query", query.QueryToString(q)))
+
+		if _, err := tx.Exec(ctx, q, id); err != nil {
+			db.log.Error("failed to lock artist dedup keys", sl.Err(err))
+			return err
+		}
+
+		// duplicates among the artist's own songs were there before the
+		// rename, GET /duplicates reports them
+		q = `
+			SELECT o.id
+			FROM library l
+			JOIN library o ON o.dedup_key = l.dedup_key AND o.artist_id <> l.artist_id AND o.deleted_at IS NULL
+			WHERE l.artist_id = $1 AND l.deleted_at IS NULL
+			ORDER BY o.id
+			LIMIT 1;
+		`
+		db.log.Debug("check artist duplicates query", slog.String("query", query.QueryToString(q)))
+
+		var dupID int
+		err := tx.QueryRow(ctx, q, id).Scan(&dupID)
+		if err == nil {
+			db.log.Error("song is already in the library", slog.Int("id", dupID))
+			return &storage.DuplicateSongError{ID: dupID}
+		}
+		if err != pgx.ErrNoRows {
+			db.log.Error("failed to check artist duplicates", sl.Err(err))
+			return err
+		}
+	}
+
+	db.log.Info("artist was successfully updated", slog.Int("id", id))
+	return nil
+}
+
+func (db *LibraryDB) DeleteArtist(ctx context.Context, tx pgx.Tx, artistID int, requestID string) error {
+	const op = "storage.library.DeleteArtist"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		DELETE FROM artists
+		WHERE id = $1
+		RETURNING id;
+	`
+	db.log.Debug("delete artist query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, artistID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("artist not found", slog.Int("artist_id", artistID))
+			return errors.New("artist not found")
+		}
+		if pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("artist still has songs", slog.Int("artist_id", artistID))
+			return errors.New("artist still has songs in library")
+		}
+		db.log.Error("failed to delete artist", sl.Err(err))
+		return err
+	}
+
+	db.log.Info("artist was successfully deleted", slog.Int("id", id))
+	return nil
+}
+
+// getOrCreateArtist returns the id of the artist with the given name, matched
+// case-insensitively, creating the artist when it does not exist yet.
+func (db *LibraryDB) getOrCreateArtist(ctx context.Context, tx pgx.Tx, name string) (int, error) {
+	q := `
+		INSERT INTO artists (name)
+		VALUES ($1)
+		ON CONFLICT (LOWER(name)) DO UPDATE SET name = artists.name
+		RETURNING id;
+	`
+	db.log.Debug("get or create artist query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, dto.NormalizeName(name)).Scan(&id); err != nil {
+		db.log.Error("failed to get or create artist", sl.Err(err))
+		return 0, err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,200 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const attachmentColumns = `
+	id, song_id, kind, filename, content_type, size, sha256, thumbnail_key IS NOT NULL, created_at, blob_key, thumbnail_key
+`
+
+func attachmentFields(a *models.Attachment) []any {
+	return []any{
+		&a.ID, &a.SongID, &a.Kind, &a.Filename, &a.ContentType, &a.Size, &a.SHA256, &a.HasThumbnail, &a.CreatedAt, &a.BlobKey, &a.ThumbnailKey,
+	}
+}
+
+func (db *LibraryDB) SaveAttachment(ctx context.Context, tx pgx.Tx, model dto.AttachmentDB, requestID string) (models.Attachment, error) {
+	const op = "storage.library.SaveAttachment"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		INSERT INTO attachments
+		(song_id, kind, filename, content_type, size, sha256, blob_key, thumbnail_key)
+		SELECT id, $2, $3, $4, $5, $6, $7, $8
+		FROM library
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING ` + attachmentColumns + `;`
+	db.log.Debug("save attachment query", slog.String("query", query.QueryToString(q)))
+
+	var att models.Attachment
+	if err := tx.QueryRow(ctx, q, model.SongID, model.Kind, model.Filename, model.ContentType,
+		model.Size, model.SHA256, model.BlobKey, model.ThumbnailKey).Scan(attachmentFields(&att)...); err != nil {
+		if err == pgx.ErrNoRows || pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("song not found", slog.Int("song_id", model.SongID))
+			return models.Attachment{}, errors.New("song not found")
+		}
+		db.log.Error("failed to save attachment", sl.Err(err))
+		return models.Attachment{}, err
+	}
+
+	db.log.Info("attachment was successfully saved", slog.Int("id", att.ID))
+	return att, nil
+}
+
+func (db *LibraryDB) GetAttachments(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]models.Attachment, error) {
+	const op = "storage.library.GetAttachments"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return nil, err
+	}
+
+	q := `
+		SELECT ` + attachmentColumns + `
+		FROM attachments
+		WHERE song_id = $1
+		ORDER BY kind, id;
+	`
+	db.log.Debug("get attachments query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, songID)
+	if err != nil {
+		db.log.Error("failed to get attachments", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []models.Attachment{}
+	for rows.Next() {
+		var att models.Attachment
+		if err := rows.Scan(attachmentFields(&att)...); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		attachments = append(attachments, att)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("attachments were successfully retrieved", slog.Int("song_id", songID), slog.Int("count", len(attachments)))
+	return attachments, nil
+}
+
+// GetAttachment returns the attachment, attachments of songs in the trash are
+// not found until the song is restored.
+func (db *LibraryDB) GetAttachment(ctx context.Context, tx pgx.Tx, attachmentID int, requestID string) (models.Attachment, error) {
+	const op = "storage.library.GetAttachment"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT ` + attachmentColumns + `
+		FROM attachments
+		WHERE id = $1
+			AND EXISTS (SELECT 1 FROM library l WHERE l.id = attachments.song_id AND l.deleted_at IS NULL);
+	`
+	db.log.Debug("get attachment query", slog.String("query", query.QueryToString(q)))
+
+	var att models.Attachment
+	if err := tx.QueryRow(ctx, q, attachmentID).Scan(attachmentFields(&att)...); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("attachment not found", slog.Int("attachment_id", attachmentID))
+			return models.Attachment{}, errors.New("attachment not found")
+		}
+		db.log.Error("failed to get attachment", sl.Err(err))
+		return models.Attachment{}, err
+	}
+
+	db.log.Info("attachment was successfully retrieved", slog.Int("id", att.ID))
+	return att, nil
+}
+
+// DeleteAttachment deletes the row and returns the blob keys to remove once
+// the transaction is committed.
+func (db *LibraryDB) DeleteAttachment(ctx context.Context, tx pgx.Tx, attachmentID int, requestID string) ([]string, error) {
+	const op = "storage.library.DeleteAttachment"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		DELETE FROM attachments
+		WHERE id = $1
+			AND EXISTS (SELECT 1 FROM library l WHERE l.id = attachments.song_id AND l.deleted_at IS NULL)
+		RETURNING blob_key, thumbnail_key;
+	`
+	db.log.Debug("delete attachment query", slog.String("query", query.QueryToString(q)))
+
+	var key string
+	var thumbnailKey *string
+	if err := tx.QueryRow(ctx, q, attachmentID).Scan(&key, &thumbnailKey); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("attachment not found", slog.Int("attachment_id", attachmentID))
+			return nil, errors.New("attachment not found")
+		}
+		db.log.Error("failed to delete attachment", sl.Err(err))
+		return nil, err
+	}
+
+	keys := []string{key}
+	if thumbnailKey != nil {
+		keys = append(keys, *thumbnailKey)
+	}
+
+	db.log.Info("attachment was successfully deleted", slog.Int("id", attachmentID))
+	return keys, nil
+}
+
+// GetSongBlobKeys returns the blob keys of all attachments of the song, the
+// rows go away with the song but the blobs have to be removed by the caller.
+func (db *LibraryDB) GetSongBlobKeys(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]string, error) {
+	const op = "storage.library.GetSongBlobKeys"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT blob_key FROM attachments WHERE song_id = $1
+		UNION ALL
+		SELECT thumbnail_key FROM attachments WHERE song_id = $1 AND thumbnail_key IS NOT NULL;
+	`
+	db.log.Debug("get song blob keys query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, songID)
+	if err != nil {
+		db.log.Error("failed to get song blob keys", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	return keys, nil
+}
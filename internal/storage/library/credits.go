@@ -0,0 +1,125 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AddSongCredit credits the artist on the song with the given role, unknown
+// artists are created. Returns the artist ID.
+func (db *LibraryDB) AddSongCredit(ctx context.Context, tx pgx.Tx, songID int, credit dto.SongCredit, requestID string) (int, error) {
+	const op = "storage.library.AddSongCredit"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return 0, err
+	}
+
+	artistID, err := db.getOrCreateArtist(ctx, tx, credit.Artist)
+	if err != nil {
+		return 0, err
+	}
+
+	q := `
+		SELECT COALESCE(MAX(position), 0) + 1
+		FROM song_artists
+		WHERE song_id = $1 AND role = $2;
+	`
+	db.log.Debug("next credit position query", slog.String("query", query.QueryToString(q)))
+
+	var position int
+	if err := tx.QueryRow(ctx, q, songID, credit.Role).Scan(&position); err != nil {
+		db.log.Error("failed to get next credit position", sl.Err(err))
+		return 0, err
+	}
+
+	if err := db.saveCredit(ctx, tx, songID, artistID, credit.Role, position); err != nil {
+		return 0, err
+	}
+
+	db.log.Info("song credit was successfully added", slog.Int("song_id", songID), slog.Int("artist_id", artistID), slog.String("role", credit.Role))
+	return artistID, nil
+}
+
+func (db *LibraryDB) RemoveSongCredit(ctx context.Context, tx pgx.Tx, songID int, artistID int, role string, requestID string) error {
+	const op = "storage.library.RemoveSongCredit"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	if role == dto.RolePrimary {
+		db.log.Error("primary artist can not be removed", slog.Int("song_id", songID))
+		return errors.New("primary artist can not be removed, update the song group instead")
+	}
+
+	q := `
+		DELETE FROM song_artists
+		WHERE song_id = $1 AND artist_id = $2 AND role = $3;
+	`
+	db.log.Debug("remove song credit query", slog.String("query", query.QueryToString(q)))
+
+	tag, err := tx.Exec(ctx, q, songID, artistID, role)
+	if err != nil {
+		db.log.Error("failed to remove song credit", sl.Err(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		db.log.Error("song credit not found", slog.Int("song_id", songID), slog.Int("artist_id", artistID), slog.String("role", role))
+		return errors.New("song credit not found")
+	}
+
+	db.log.Info("song credit was successfully removed", slog.Int("song_id", songID), slog.Int("artist_id", artistID), slog.String("role", role))
+	return nil
+}
+
+func (db *LibraryDB) saveCredit(ctx context.Context, tx pgx.Tx, songID int, artistID int, role string, position int) error {
+	q := `
+		INSERT INTO song_artists
+		(song_id, artist_id, role, position)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (song_id, artist_id, role) DO NOTHING;
+	`
+	db.log.Debug("save song credit query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID, artistID, role, position); err != nil {
+		if pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return errors.New("song not found")
+		}
+		db.log.Error("failed to save song credit", sl.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+// setPrimaryCredit keeps the primary credit in line with library.artist_id,
+// an artist which becomes primary is no longer credited as featuring.
+func (db *LibraryDB) setPrimaryCredit(ctx context.Context, tx pgx.Tx, songID int, artistID int) error {
+	q := `
+		DELETE FROM song_artists
+		WHERE song_id = $1
+			AND (role = 'primary' OR (role = 'featuring' AND artist_id = $2));
+	`
+	db.log.Debug("reset primary credit query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID, artistID); err != nil {
+		db.log.Error("failed to reset primary credit", sl.Err(err))
+		return err
+	}
+
+	return db.saveCredit(ctx, tx, songID, artistID, dto.RolePrimary, 0)
+}
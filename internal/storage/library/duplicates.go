@@ -0,0 +1,342 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/query"
+	"music-library/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetDuplicates returns the groups of songs in the library that have the same
+// normalized group and song name, the trash is left out.
+func (db *LibraryDB) GetDuplicates(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.Duplicates, error) {
+	const op = "storage.library.GetDuplicates"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := fmt.Sprintf(`
+		SELECT l.dedup_key, %s
+		FROM library l
+		JOIN artists a ON a.id = l.artist_id
+		WHERE l.deleted_at IS NULL AND l.dedup_key IN (
+			SELECT dedup_key
+			FROM library
+			WHERE deleted_at IS NULL
+			GROUP BY dedup_key
+			HAVING COUNT(*) > 1
+			ORDER BY dedup_key
+			LIMIT $1
+			OFFSET $2
+		)
+		ORDER BY l.dedup_key, l.id;
+	`, songColumns)
+	db.log.Debug("get duplicates query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, limit, offset)
+	if err != nil {
+		db.log.Error("failed to get duplicates", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	duplicates := []models.Duplicates{}
+	var lastKey string
+	for rows.Next() {
+		var key string
+		var song models.Song
+		if err := rows.Scan(append([]any{&key}, songFields(&song)...)...); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		if len(duplicates) == 0 || key != lastKey {
+			duplicates = append(duplicates, models.Duplicates{Group: song.Group, Song: song.Song})
+			lastKey = key
+		}
+		last := &duplicates[len(duplicates)-1]
+		last.Songs = append(last.Songs, song)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("duplicates were successfully retrieved", slog.Int("count", len(duplicates)))
+	return duplicates, nil
+}
+
+// MergeSong moves the links, tags, genres, attachments, playlist entries,
+// album tracks, credits, translations, synced lyrics and relations of the song
+// to the song it is merged into. Rows the target already has, entries that
+// would repeat it in a playlist without duplicates or an album, and relations
+// between the two songs stay behind and go away with the song.
+func (db *LibraryDB) MergeSong(ctx context.Context, tx pgx.Tx, songID int, intoID int, requestID string) error {
+	const op = "storage.library.MergeSong"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if songID == intoID {
+		db.log.Error("song can not be merged into itself", slog.Int("song_id", songID))
+		return errors.New("song can not be merged into itself")
+	}
+
+	q := `
+		SELECT id
+		FROM library
+		WHERE id IN ($1, $2) AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE;
+	`
+	db.log.Debug("lock merged songs query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, songID, intoID)
+	if err != nil {
+		db.log.Error("failed to lock merged songs", sl.Err(err))
+		return err
+	}
+	found := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			db.log.Error("failed to scan row", sl.Err(err))
+			return err
+		}
+		found[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return err
+	}
+
+	for _, id := range []int{songID, intoID} {
+		if !found[id] {
+			db.log.Error("song not found", slog.Int("song_id", id))
+			return errors.New("song not found")
+		}
+	}
+
+	queries := []struct {
+		name string
+		q    string
+	}{
+		{"merge links", `
+			INSERT INTO song_links (song_id, provider, url, label, is_primary)
+			SELECT $2, provider, url, label,
+				is_primary AND NOT EXISTS (SELECT 1 FROM song_links WHERE song_id = $2 AND is_primary)
+			FROM song_links
+			WHERE song_id = $1
+			ON CONFLICT (song_id, url) DO NOTHING;
+		`},
+		{"merge tags", `
+			INSERT INTO song_tags (song_id, tag_id)
+			SELECT $2, tag_id FROM song_tags WHERE song_id = $1
+			ON CONFLICT DO NOTHING;
+		`},
+		{"merge genres", `
+			INSERT INTO song_genres (song_id, genre_id)
+			SELECT $2, genre_id FROM song_genres WHERE song_id = $1
+			ON CONFLICT DO NOTHING;
+		`},
+		{"merge attachments", `
+			UPDATE attachments SET song_id = $2 WHERE song_id = $1;
+		`},
+		{"merge playlist entries", `
+			UPDATE playlist_entries pe
+			SET song_id = $2
+			FROM playlists p
+			WHERE p.id = pe.playlist_id AND pe.song_id = $1 AND (
+				p.allow_duplicates
+				OR NOT EXISTS (SELECT 1 FROM playlist_entries t WHERE t.playlist_id = pe.playlist_id AND t.song_id = $2)
+			);
+		`},
+		{"merge album tracks", `
+			UPDATE album_tracks tr
+			SET song_id = $2
+			WHERE tr.song_id = $1
+				AND NOT EXISTS (SELECT 1 FROM album_tracks t WHERE t.album_id = tr.album_id AND t.song_id = $2);
+		`},
+		// the target keeps its own primary artist
+		{"merge song artists", `
+			INSERT INTO song_artists (song_id, artist_id, role, position)
+			SELECT $2, artist_id, role, position
+			FROM song_artists
+			WHERE song_id = $1 AND role <> 'primary'
+			ON CONFLICT DO NOTHING;
+		`},
+		{"merge song people", `
+			INSERT INTO song_people (song_id, person_id, role)
+			SELECT $2, person_id, role FROM song_people WHERE song_id = $1
+			ON CONFLICT DO NOTHING;
+		`},
+		// the sections follow the lyrics by ON UPDATE CASCADE
+		{"merge translations", `
+			UPDATE lyrics ly
+			SET song_id = $2
+			WHERE ly.song_id = $1 AND NOT ly.is_original
+				AND NOT EXISTS (SELECT 1 FROM lyrics t WHERE t.song_id = $2 AND t.lang = ly.lang);
+		`},
+		{"merge synced lyrics", `
+			UPDATE synced_lyrics sl
+			SET song_id = $2
+			WHERE sl.song_id = $1
+				AND NOT EXISTS (SELECT 1 FROM synced_lyrics t WHERE t.song_id = $2 AND t.lang = sl.lang);
+		`},
+		{"merge versions", `
+			INSERT INTO song_relations (song_id, original_id, relation, created_at)
+			SELECT $2, original_id, relation, created_at
+			FROM song_relations
+			WHERE song_id = $1 AND original_id <> $2
+			ON CONFLICT DO NOTHING;
+		`},
+		{"merge originals", `
+			INSERT INTO song_relations (song_id, original_id, relation, created_at)
+			SELECT song_id, $2, relation, created_at
+			FROM song_relations
+			WHERE original_id = $1 AND song_id <> $2
+			ON CONFLICT DO NOTHING;
+		`},
+		{"delete merged relations", `
+			DELETE FROM song_relations WHERE song_id = $1 OR original_id = $1;
+		`},
+	}
+
+	// the relations are moved under the lock LinkSongs takes, so a concurrent
+	// link can not close a cycle with them
+	q = `
+		SELECT pg_advisory_xact_lock(hashtext('song_relations'));
+	`
+	db.log.Debug("lock song relations query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q); err != nil {
+		db.log.Error("failed to lock song relations", sl.Err(err))
+		return err
+	}
+
+	for _, mq := range queries {
+		db.log.Debug(mq.name+" query", slog.String("query", query.QueryToString(mq.q)))
+		if _, err := tx.Exec(ctx, mq.q, songID, intoID); err != nil {
+			db.log.Error("failed to "+mq.name, sl.Err(err))
+			return err
+		}
+	}
+
+	// a song that was a version of one of the two and an original of the
+	// other is now both for the target
+	q = `
+		WITH RECURSIVE originals(id) AS (
+			SELECT original_id FROM song_relations WHERE song_id = $1
+			UNION
+			SELECT r.original_id
+			FROM song_relations r
+			JOIN originals o ON r.song_id = o.id
+		)
+		SELECT EXISTS (SELECT 1 FROM originals WHERE id = $1);
+	`
+	db.log.Debug("check relation cycle query", slog.String("query", query.QueryToString(q)))
+
+	var cycle bool
+	if err := tx.QueryRow(ctx, q, intoID).Scan(&cycle); err != nil {
+		db.log.Error("failed to check relation cycle", sl.Err(err))
+		return err
+	}
+	if cycle {
+		db.log.Error("merge would create a relation cycle", slog.Int("song_id", songID), slog.Int("into_id", intoID))
+		return errors.New("the merged songs are versions of each other through another song")
+	}
+
+	db.log.Info("song was successfully merged", slog.Int("song_id", songID), slog.Int("into_id", intoID))
+	return nil
+}
+
+// GetDedupKey returns the key the song would have in the library, songs with
+// the same key are duplicates.
+func (db *LibraryDB) GetDedupKey(ctx context.Context, tx pgx.Tx, group string, song string, requestID string) (string, error) {
+	const op = "storage.library.GetDedupKey"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	return db.dedupKey(ctx, tx, group, song)
+}
+
+// dedupKey normalizes the group and song name the same way as the dedup_key
+// column: case, whitespace and diacritics do not matter.
+func (db *LibraryDB) dedupKey(ctx context.Context, tx pgx.Tx, group string, song string) (string, error) {
+	q := `
+		SELECT normalize_key($1) || E'\n' || normalize_key($2);
+	`
+	db.log.Debug("dedup key query", slog.String("query", query.QueryToString(q)))
+
+	var key string
+	if err := tx.QueryRow(ctx, q, group, song).Scan(&key); err != nil {
+		db.log.Error("failed to get dedup key", sl.Err(err))
+		return "", err
+	}
+
+	return key, nil
+}
+
+// checkDuplicate fails with storage.DuplicateSongError when another song in
+// the library has the key. The key stays locked until the end of the
+// transaction so two saves of the same song can not both pass the check.
+func (db *LibraryDB) checkDuplicate(ctx context.Context, tx pgx.Tx, key string, songID int) error {
+	q := `
+		SELECT pg_advisory_xact_lock(hashtext($1));
+	`
+	db.log.Debug("lock dedup key query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, key); err != nil {
+		db.log.Error("failed to lock dedup key", sl.Err(err))
+		return err
+	}
+
+	q = `
+		SELECT id
+		FROM library
+		WHERE dedup_key = $1 AND deleted_at IS NULL AND id <> $2
+		ORDER BY id
+		LIMIT 1;
+	`
+	db.log.Debug("check duplicate query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, key, songID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		db.log.Error("failed to check duplicate", sl.Err(err))
+		return err
+	}
+
+	db.log.Error("song is already in the library", slog.Int("id", id))
+	return &storage.DuplicateSongError{ID: id}
+}
+
+// refreshDedupKey recomputes the key after the group or the song name of the
+// song has changed and checks it against the library.
+func (db *LibraryDB) refreshDedupKey(ctx context.Context, tx pgx.Tx, songID int) error {
+	q := `
+		UPDATE library l
+		SET dedup_key = normalize_key(a.name) || E'\n' || normalize_key(l.song)
+		FROM artists a
+		WHERE a.id = l.artist_id AND l.id = $1
+		RETURNING l.dedup_key;
+	`
+	db.log.Debug("refresh dedup key query", slog.String("query", query.QueryToString(q)))
+
+	var key string
+	if err := tx.QueryRow(ctx, q, songID).Scan(&key); err != nil {
+		db.log.Error("failed to refresh dedup key", sl.Err(err))
+		return err
+	}
+
+	return db.checkDuplicate(ctx, tx, key, songID)
+}
@@ -0,0 +1,124 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// facetSize is how many of the most frequent groups and tags a facet has,
+// decades and years are all returned in order.
+const facetSize = 20
+
+// facetQueries are the value of each facet for library aliased as l joined
+// with artists aliased as a, the joins it needs and the order of its values,
+// top keeps only the facetSize most frequent ones.
+var facetQueries = map[string]struct {
+	value string
+	join  string
+	order string
+	top   bool
+}{
+	"group": {
+		value: "a.name",
+		order: "COUNT(*) DESC, value COLLATE natural_sort",
+		top:   true,
+	},
+	"decade": {
+		value: "EXTRACT(YEAR FROM l.release_date)::int / 10 * 10",
+		order: "value",
+	},
+	"year": {
+		value: "EXTRACT(YEAR FROM l.release_date)::int",
+		order: "value",
+	},
+	"tag": {
+		value: "ft.name",
+		join:  "JOIN song_tags fst ON fst.song_id = l.id JOIN tags ft ON ft.id = fst.tag_id",
+		order: "COUNT(*) DESC, value COLLATE natural_sort",
+		top:   true,
+	},
+}
+
+// GetLibraryFacets counts the songs that match the filters by each facet,
+// songs without a value are left out of a facet. A decade is named by its
+// first year.
+func (db *LibraryDB) GetLibraryFacets(ctx context.Context, tx pgx.Tx, filters dto.Filters, facets []string, requestID string) (map[string][]models.FacetValue, error) {
+	const op = "storage.library.GetLibraryFacets"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	filterStr, params, err := db.libraryFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if filters.Fuzzy != nil {
+		if err := db.setSimilarityThreshold(ctx, tx, filters.FuzzyThreshold); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make(map[string][]models.FacetValue, len(facets))
+	for _, facet := range facets {
+		fq, ok := facetQueries[facet]
+		if !ok {
+			return nil, fmt.Errorf("failed to convert facet %q", facet)
+		}
+
+		limit := ""
+		if fq.top {
+			limit = fmt.Sprintf("LIMIT %d", facetSize)
+		}
+
+		q := fmt.Sprintf(`
+			SELECT value::text, COUNT(*)
+			FROM (
+				SELECT %s AS value
+				FROM library l
+				JOIN artists a ON a.id = l.artist_id
+				%s
+				WHERE %s
+			) f
+			WHERE value IS NOT NULL
+			GROUP BY value
+			ORDER BY %s
+			%s;
+		`, fq.value, fq.join, filterStr, fq.order, limit)
+		db.log.Debug("get library facet query", slog.String("facet", facet), slog.String("query", query.QueryToString(q)))
+
+		rows, err := tx.Query(ctx, q, params...)
+		if err != nil {
+			db.log.Error("failed to get library facet", slog.String("facet", facet), sl.Err(err))
+			return nil, err
+		}
+
+		values := []models.FacetValue{}
+		for rows.Next() {
+			var value models.FacetValue
+			if err := rows.Scan(&value.Value, &value.Count); err != nil {
+				rows.Close()
+				db.log.Error("failed to scan row", sl.Err(err))
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			db.log.Error("failed to scan rows", sl.Err(err))
+			return nil, err
+		}
+		result[facet] = values
+	}
+
+	db.log.Info("library facets were successfully counted", slog.Int("facets", len(result)))
+	return result, nil
+}
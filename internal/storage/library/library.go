@@ -2,6 +2,7 @@ package library
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -11,6 +12,8 @@ import (
 	"music-library/internal/lib/logger/with"
 	"music-library/internal/lib/storage/query"
 	"music-library/internal/lib/storage/tools"
+	"slices"
+	"strconv"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -23,47 +26,176 @@ func NewLibraryDB(log *slog.Logger) *LibraryDB {
 	return &LibraryDB{log: log}
 }
 
+// releaseDateColumn renders the release date of library aliased as l up to its
+// precision, the same way as partialdate.Date.String.
+const releaseDateColumn = `
+	CASE l.release_date_precision
+		WHEN 'year' THEN to_char(l.release_date, 'YYYY')
+		WHEN 'month' THEN to_char(l.release_date, 'MM.YYYY')
+		ELSE to_char(l.release_date, 'DD.MM.YYYY')
+	END`
+
+// songColumns selects a models.Song from library aliased as l joined with
+// artists aliased as a, in the order of songFields.
+const songColumns = `
+	l.id, l.artist_id, a.name,
+	ARRAY(
+		SELECT ca.name FROM song_artists sa
+		JOIN artists ca ON ca.id = sa.artist_id
+		WHERE sa.song_id = l.id AND sa.role = 'featuring'
+		ORDER BY sa.position, ca.name
+	),
+	ARRAY(
+		SELECT ca.name FROM song_artists sa
+		JOIN artists ca ON ca.id = sa.artist_id
+		WHERE sa.song_id = l.id AND sa.role = 'producer'
+		ORDER BY sa.position, ca.name
+	),
+	l.song, ` + releaseDateColumn + `, l.release_date_precision, l.text,
+	COALESCE((SELECT ln.url FROM song_links ln WHERE ln.song_id = l.id AND ln.is_primary), ''),
+	l.duration, l.bpm, l.musical_key, l.isrc, l.explicit,
+	ARRAY(
+		SELECT t.name FROM song_tags st
+		JOIN tags t ON t.id = st.tag_id
+		WHERE st.song_id = l.id
+		ORDER BY t.name
+	),
+	ARRAY(
+		SELECT g.name FROM song_genres sg
+		JOIN genres g ON g.id = sg.genre_id
+		WHERE sg.song_id = l.id
+		ORDER BY g.name
+	)
+`
+
+func songFields(song *models.Song) []any {
+	return []any{
+		&song.ID, &song.ArtistID, &song.Group, &song.Featuring, &song.Producers, &song.Song, &song.ReleaseDate, &song.ReleaseDatePrecision, &song.Text, &song.Patronymic,
+		&song.Duration, &song.BPM, &song.Key, &song.ISRC, &song.Explicit, &song.Tags, &song.Genres,
+	}
+}
+
 func (db *LibraryDB) SaveSong(ctx context.Context, tx pgx.Tx, model dto.SongDB, requestID string) (int, error) {
 	const op = "storage.library.SaveSong"
 
 	db.log = with.WithOpAndRequestID(db.log, op, requestID)
 
+	key, err := db.dedupKey(ctx, tx, model.Group, model.Song)
+	if err != nil {
+		return 0, err
+	}
+	if err := db.checkDuplicate(ctx, tx, key, 0); err != nil {
+		return 0, err
+	}
+
+	artistID, err := db.getOrCreateArtist(ctx, tx, model.Group)
+	if err != nil {
+		return 0, err
+	}
+
 	q := `
 		INSERT INTO library 
-		(group_name, song, release_date, text, patronymic)
-		VALUES ($1, $2, $3, $4, $5)
+		(artist_id, song, release_date, release_date_precision, text, duration, bpm, musical_key, isrc, explicit, dedup_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id;
 	`
 	db.log.Debug("save new song query", slog.String("query", query.QueryToString(q)))
 
 	var id int
-	if err := tx.QueryRow(ctx, q, model.Group, model.Song, model.ReleaseDate, model.Text, model.Patronymic).Scan(&id); err != nil {
+	if err := tx.QueryRow(ctx, q, artistID, model.Song, model.ReleaseDate.Time, model.ReleaseDate.Precision, model.Text,
+		model.Duration, model.BPM, model.Key, model.ISRC, model.Explicit, key).Scan(&id); err != nil {
 		db.log.Error("failed to save a new song", sl.Err(err))
 		return 0, err
 	}
 
+	if err := db.setPrimaryCredit(ctx, tx, id, artistID); err != nil {
+		return 0, err
+	}
+
+	if err := db.syncOriginalLyrics(ctx, tx, id); err != nil {
+		return 0, err
+	}
+
+	for i, name := range model.Featuring {
+		featuringID, err := db.getOrCreateArtist(ctx, tx, name)
+		if err != nil {
+			return 0, err
+		}
+		if err := db.saveCredit(ctx, tx, id, featuringID, dto.RoleFeaturing, i+1); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, link := range model.Links {
+		if _, err := db.saveLink(ctx, tx, id, link); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := db.saveRevision(ctx, tx, id, nil, requestID); err != nil {
+		return 0, err
+	}
+
 	db.log.Info("new song was successfully saved", slog.Int("id", id))
 	return id, nil
 }
 
-func (db *LibraryDB) GetLibray(ctx context.Context, tx pgx.Tx, filters dto.Filters, limit int, offset int, requestID string) ([]models.Song, error) {
+// GetLibray returns a page of songs by the offset, or with a keyset the page
+// after or before its sort values. The songs come in the order of the listing
+// either way.
+func (db *LibraryDB) GetLibray(ctx context.Context, tx pgx.Tx, filters dto.Filters, sort []dto.SortKey, limit int, offset int, keyset *dto.Keyset, requestID string) ([]models.Song, error) {
 	const op = "storage.library.GetLibrary"
 
 	db.log = with.WithOpAndRequestID(db.log, op, requestID)
 
-	filterStr, params, err := tools.GetFilters(filters)
+	filterStr, params, err := db.libraryFilter(filters)
 	if err != nil {
-		db.log.Error("failed to convert filters to SQL query", sl.Err(err))
 		return nil, err
 	}
 
+	rank, params := tools.GetRank(filters, params)
+	similarity, params := tools.GetSimilarity(filters, params)
+
+	order := dto.Order(sort, filters)
+	backward := keyset != nil && keyset.Before
+
+	orderStr, err := tools.GetOrder(order, backward)
+	if err != nil {
+		db.log.Error("failed to convert sort to SQL query", sl.Err(err))
+		return nil, err
+	}
+
+	sortValues, err := tools.GetSortValues(order, rank, similarity)
+	if err != nil {
+		db.log.Error("failed to convert sort to SQL query", sl.Err(err))
+		return nil, err
+	}
+
+	if keyset != nil {
+		var keysetStr string
+		keysetStr, params, err = tools.GetKeyset(order, *keyset, rank, similarity, params)
+		if err != nil {
+			db.log.Error("failed to convert keyset to SQL query", sl.Err(err))
+			return nil, err
+		}
+		filterStr = fmt.Sprintf("%s AND %s", filterStr, keysetStr)
+	}
+
+	if filters.Fuzzy != nil {
+		if err := db.setSimilarityThreshold(ctx, tx, filters.FuzzyThreshold); err != nil {
+			return nil, err
+		}
+	}
+
 	q := fmt.Sprintf(`
-		SELECT id, group_name, song, to_char(release_date, 'DD.MM.YYYY'), text, patronymic
-		FROM library
+		SELECT %s, %s AS rank, %s AS similarity, %s
+		FROM library l
+		JOIN artists a ON a.id = l.artist_id
 		WHERE %s
+		%s
 		LIMIT $%d
 		OFFSET $%d;
-	`, filterStr, len(params)+1, len(params)+2)
+	`, songColumns, rank, similarity, sortValues, filterStr, orderStr, len(params)+1, len(params)+2)
 
 	db.log.Debug("get library query", slog.String("query", query.QueryToString(q)))
 
@@ -79,55 +211,180 @@ func (db *LibraryDB) GetLibray(ctx context.Context, tx pgx.Tx, filters dto.Filte
 	var songs []models.Song
 	for rows.Next() {
 		var song models.Song
-		if err := rows.Scan(&song.ID, &song.Group, &song.Song, &song.ReleaseDate, &song.Text, &song.Patronymic); err != nil {
+		if err := rows.Scan(append(songFields(&song), &song.Rank, &song.Similarity, &song.SortValues)...); err != nil {
 			db.log.Error("failed to scan row", sl.Err(err))
 			return nil, err
 		}
 		songs = append(songs, song)
 	}
 
-	if rows.Err() != nil {
-		db.log.Error("failed to scan rows", sl.Err(rows.Err()))
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
 		return nil, err
 	}
 
+	if backward {
+		slices.Reverse(songs)
+	}
+
 	db.log.Info("library was successfully retrieved", slog.Int("count", len(songs)))
 	return songs, nil
 }
 
-func (db *LibraryDB) GetSongText(ctx context.Context, tx pgx.Tx, songID int, requestID string) (string, error) {
+// CountLibrary returns how many songs match the filters. With estimate the
+// planner guesses the number from the table statistics instead of counting,
+// which stays fast on large result sets but can be off.
+func (db *LibraryDB) CountLibrary(ctx context.Context, tx pgx.Tx, filters dto.Filters, estimate bool, requestID string) (int, error) {
+	const op = "storage.library.CountLibrary"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	filterStr, params, err := db.libraryFilter(filters)
+	if err != nil {
+		return 0, err
+	}
+
+	if filters.Fuzzy != nil {
+		if err := db.setSimilarityThreshold(ctx, tx, filters.FuzzyThreshold); err != nil {
+			return 0, err
+		}
+	}
+
+	q := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM library l
+		JOIN artists a ON a.id = l.artist_id
+		WHERE %s;
+	`, filterStr)
+
+	if !estimate {
+		db.log.Debug("count library query", slog.String("query", query.QueryToString(q)))
+
+		var total int
+		if err := tx.QueryRow(ctx, q, params...).Scan(&total); err != nil {
+			db.log.Error("failed to count library", sl.Err(err))
+			return 0, err
+		}
+
+		db.log.Info("library was successfully counted", slog.Int("total", total))
+		return total, nil
+	}
+
+	q = fmt.Sprintf(`
+		EXPLAIN (FORMAT JSON)
+		SELECT 1
+		FROM library l
+		JOIN artists a ON a.id = l.artist_id
+		WHERE %s;
+	`, filterStr)
+
+	db.log.Debug("estimate library query", slog.String("query", query.QueryToString(q)))
+
+	var explain string
+	if err := tx.QueryRow(ctx, q, params...).Scan(&explain); err != nil {
+		db.log.Error("failed to estimate library", sl.Err(err))
+		return 0, err
+	}
+
+	var plans []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(explain), &plans); err != nil || len(plans) == 0 {
+		db.log.Error("failed to read query plan", slog.String("plan", explain))
+		return 0, errors.New("failed to estimate library")
+	}
+
+	total := int(plans[0].Plan.Rows)
+	db.log.Info("library was successfully estimated", slog.Int("total", total))
+	return total, nil
+}
+
+// libraryFilter returns the condition for the songs of the listing, the ones
+// out of the trash that match the filters.
+func (db *LibraryDB) libraryFilter(filters dto.Filters) (string, []any, error) {
+	filterStr, params, err := tools.GetFilters(filters)
+	if err != nil {
+		db.log.Error("failed to convert filters to SQL query", sl.Err(err))
+		return "", nil, err
+	}
+	if filterStr == "" {
+		filterStr = "TRUE"
+	}
+
+	return fmt.Sprintf("l.deleted_at IS NULL AND (%s)", filterStr), params, nil
+}
+
+// setSimilarityThreshold sets the similarity the trigram % operator needs
+// for the rest of the transaction.
+func (db *LibraryDB) setSimilarityThreshold(ctx context.Context, tx pgx.Tx, threshold any) error {
+	val, ok := threshold.(float64)
+	if !ok {
+		return errors.New("failed to convert fuzzy threshold")
+	}
+
+	q := `
+		SELECT set_config('pg_trgm.similarity_threshold', $1, true);
+	`
+	db.log.Debug("set similarity threshold query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, strconv.FormatFloat(val, 'f', -1, 64)); err != nil {
+		db.log.Error("failed to set similarity threshold", sl.Err(err))
+		return err
+	}
+	return nil
+}
+
+// GetSongText returns the lyrics in the requested language together with the
+// language they are in. Without an exact match a translation with the same
+// primary language subtag is used unless the original already is in that
+// language, the original lyrics are the fallback.
+func (db *LibraryDB) GetSongText(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) (string, string, error) {
 	const op = "storage.library.GetSongText"
 
 	db.log = with.WithOpAndRequestID(db.log, op, requestID)
 
 	q := `
-        SELECT text
-        FROM library
-        WHERE id = $1
+        SELECT COALESCE(tr.text, l.text), COALESCE(tr.lang, orig.lang, 'und')
+        FROM library l
+        LEFT JOIN lyrics orig ON orig.song_id = l.id AND orig.is_original
+        LEFT JOIN LATERAL (
+            SELECT ly.lang, ly.text
+            FROM lyrics ly
+            WHERE ly.song_id = l.id AND NOT ly.is_original
+                AND (ly.lang = $2 OR split_part(ly.lang, '-', 1) = split_part($2, '-', 1))
+            ORDER BY ly.lang = $2 DESC, ly.lang
+            LIMIT 1
+        ) tr ON tr.lang = $2 OR split_part(COALESCE(orig.lang, ''), '-', 1) <> split_part($2, '-', 1)
+        WHERE l.id = $1 AND l.deleted_at IS NULL
     `
 	db.log.Debug("get song text query", slog.String("query", query.QueryToString(q)))
 
-	var text string
-	if err := tx.QueryRow(ctx, q, songID).Scan(&text); err != nil {
+	var text, textLang string
+	if err := tx.QueryRow(ctx, q, songID, lang).Scan(&text, &textLang); err != nil {
 		if err == pgx.ErrNoRows {
 			db.log.Error("song text not found", slog.Int("song_id", songID))
-			return "", errors.New("song not found")
+			return "", "", errors.New("song not found")
 		}
 		db.log.Error("failed to get song text", sl.Err(err))
+		return "", "", err
 	}
 
-	db.log.Info("song text was successfully retrieved", slog.Int("song_id", songID))
-	return text, nil
+	db.log.Info("song text was successfully retrieved", slog.Int("song_id", songID), slog.String("lang", textLang))
+	return text, textLang, nil
 }
 
+// DeleteSong moves the song to the trash, PurgeSong removes it for good.
 func (db *LibraryDB) DeleteSong(ctx context.Context, tx pgx.Tx, songID int, requestID string) error {
 	const op = "storage.library.DeleteSong"
 
 	db.log = with.WithOpAndRequestID(db.log, op, requestID)
 
 	q := `
-        DELETE FROM library
-        WHERE id = $1
+        UPDATE library
+        SET deleted_at = NOW()
+        WHERE id = $1 AND deleted_at IS NULL
 		RETURNING id;
     `
 	db.log.Debug("delete song query", slog.String("query", query.QueryToString(q)))
@@ -147,7 +404,7 @@ func (db *LibraryDB) DeleteSong(ctx context.Context, tx pgx.Tx, songID int, requ
 		return errors.New("failed to delete song")
 	}
 
-	db.log.Info("song was successfully deleted", slog.Int("id", id))
+	db.log.Info("song was successfully moved to trash", slog.Int("id", id))
 	return nil
 }
 
@@ -155,12 +412,29 @@ func (db *LibraryDB) UpdateSong(ctx context.Context, tx pgx.Tx, updateModel dto.
 	const op = "storage.library.UpdateSong"
 
 	db.log = with.WithOpAndRequestID(db.log, op, requestID)
-	strParams, params := tools.GetUpdateParams(updateModel)
+
+	prev, err := db.songState(ctx, tx, updateModel.ID, true)
+	if err != nil {
+		return err
+	}
+
+	var artistID int
+	if updateModel.Group != nil {
+		artistID, err = db.getOrCreateArtist(ctx, tx, updateModel.Group.(string))
+		if err != nil {
+			return err
+		}
+	}
+	strParams, params := tools.GetUpdateParams(updateModel, artistID)
+	if strParams == "" {
+		// only the links are changed, the update still checks that the song exists
+		strParams = "id = id"
+	}
 
 	q := fmt.Sprintf(`
 		UPDATE library
 		SET %s
-		WHERE id = $%d
+		WHERE id = $%d AND deleted_at IS NULL
 		RETURNING id;
 	`, strParams, len(params)+1)
 
@@ -183,6 +457,39 @@ func (db *LibraryDB) UpdateSong(ctx context.Context, tx pgx.Tx, updateModel dto.
 		return errors.New("failed to update song")
 	}
 
+	if updateModel.Group != nil {
+		if err := db.setPrimaryCredit(ctx, tx, id, artistID); err != nil {
+			return err
+		}
+	}
+
+	if updateModel.Group != nil || updateModel.Song != nil {
+		if err := db.refreshDedupKey(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	if updateModel.Text != nil {
+		if err := db.syncOriginalLyrics(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	if updateModel.Patronymic != nil {
+		link := updateModel.Patronymic.(dto.LinkDB)
+		if link.URL == "" {
+			if err := db.clearPrimaryLink(ctx, tx, id); err != nil {
+				return err
+			}
+		} else if _, err := db.saveLink(ctx, tx, id, link); err != nil {
+			return err
+		}
+	}
+
+	if err := db.saveRevision(ctx, tx, id, &prev, requestID); err != nil {
+		return err
+	}
+
 	db.log.Info("song was successfully updated", slog.Int("id", id))
 	return nil
 }
@@ -0,0 +1,206 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *LibraryDB) GetSongLinks(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]models.Link, error) {
+	const op = "storage.library.GetSongLinks"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return nil, err
+	}
+
+	q := `
+		SELECT id, song_id, provider, url, label, is_primary
+		FROM song_links
+		WHERE song_id = $1
+		ORDER BY is_primary DESC, id;
+	`
+	db.log.Debug("get song links query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, songID)
+	if err != nil {
+		db.log.Error("failed to get song links", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []models.Link{}
+	for rows.Next() {
+		var link models.Link
+		if err := rows.Scan(&link.ID, &link.SongID, &link.Provider, &link.URL, &link.Label, &link.Primary); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("song links were successfully retrieved", slog.Int("song_id", songID), slog.Int("count", len(links)))
+	return links, nil
+}
+
+func (db *LibraryDB) AddSongLink(ctx context.Context, tx pgx.Tx, songID int, link dto.LinkDB, requestID string) (int, error) {
+	const op = "storage.library.AddSongLink"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return 0, err
+	}
+
+	id, err := db.saveLink(ctx, tx, songID, link)
+	if err != nil {
+		return 0, err
+	}
+
+	db.log.Info("song link was successfully saved", slog.Int("id", id))
+	return id, nil
+}
+
+// RemoveSongLink deletes the link, when it was the primary one the oldest of
+// the remaining links becomes primary.
+func (db *LibraryDB) RemoveSongLink(ctx context.Context, tx pgx.Tx, songID int, linkID int, requestID string) error {
+	const op = "storage.library.RemoveSongLink"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	q := `
+		DELETE FROM song_links
+		WHERE song_id = $1 AND id = $2
+		RETURNING is_primary;
+	`
+	db.log.Debug("remove song link query", slog.String("query", query.QueryToString(q)))
+
+	var primary bool
+	if err := tx.QueryRow(ctx, q, songID, linkID).Scan(&primary); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("song link not found", slog.Int("link_id", linkID))
+			return errors.New("song link not found")
+		}
+		db.log.Error("failed to remove song link", sl.Err(err))
+		return err
+	}
+
+	if primary {
+		q = `
+			UPDATE song_links
+			SET is_primary = TRUE
+			WHERE id = (SELECT MIN(id) FROM song_links WHERE song_id = $1);
+		`
+		db.log.Debug("promote song link query", slog.String("query", query.QueryToString(q)))
+
+		if _, err := tx.Exec(ctx, q, songID); err != nil {
+			db.log.Error("failed to promote song link", sl.Err(err))
+			return err
+		}
+	}
+
+	db.log.Info("song link was successfully removed", slog.Int("id", linkID))
+	return nil
+}
+
+// saveLink adds the link to the song or updates it when the song already has
+// this url. A primary link takes the flag over from the previous one, the
+// first link of a song is always primary.
+func (db *LibraryDB) saveLink(ctx context.Context, tx pgx.Tx, songID int, link dto.LinkDB) (int, error) {
+	if link.Primary {
+		q := `
+			UPDATE song_links
+			SET is_primary = FALSE
+			WHERE song_id = $1 AND is_primary AND url <> $2;
+		`
+		db.log.Debug("reset primary link query", slog.String("query", query.QueryToString(q)))
+
+		if _, err := tx.Exec(ctx, q, songID, link.URL); err != nil {
+			db.log.Error("failed to reset primary link", sl.Err(err))
+			return 0, err
+		}
+	}
+
+	q := `
+		INSERT INTO song_links
+		(song_id, provider, url, label, is_primary)
+		VALUES ($1, $2, $3, $4, $5 OR NOT EXISTS (
+			SELECT 1 FROM song_links WHERE song_id = $1 AND is_primary
+		))
+		ON CONFLICT (song_id, url) DO UPDATE
+		SET provider = EXCLUDED.provider,
+			label = COALESCE(EXCLUDED.label, song_links.label),
+			is_primary = song_links.is_primary OR EXCLUDED.is_primary
+		RETURNING id;
+	`
+	db.log.Debug("save song link query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, songID, link.Provider, link.URL, link.Label, link.Primary).Scan(&id); err != nil {
+		if pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return 0, errors.New("song not found")
+		}
+		db.log.Error("failed to save song link", sl.Err(err))
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// clearPrimaryLink leaves the song without a primary link, the links stay
+// among its other links.
+func (db *LibraryDB) clearPrimaryLink(ctx context.Context, tx pgx.Tx, songID int) error {
+	q := `
+		UPDATE song_links
+		SET is_primary = FALSE
+		WHERE song_id = $1 AND is_primary;
+	`
+	db.log.Debug("clear primary link query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID); err != nil {
+		db.log.Error("failed to clear primary link", sl.Err(err))
+		return err
+	}
+	return nil
+}
+
+// checkSong fails with "song not found" unless the song exists out of the
+// trash, a song in the trash is left as it was deleted until it is restored.
+func (db *LibraryDB) checkSong(ctx context.Context, tx pgx.Tx, songID int) error {
+	q := `
+		SELECT EXISTS (SELECT 1 FROM library WHERE id = $1 AND deleted_at IS NULL);
+	`
+	db.log.Debug("check song query", slog.String("query", query.QueryToString(q)))
+
+	var exists bool
+	if err := tx.QueryRow(ctx, q, songID).Scan(&exists); err != nil {
+		db.log.Error("failed to check song", sl.Err(err))
+		return err
+	}
+
+	if !exists {
+		db.log.Error("song not found", slog.Int("song_id", songID))
+		return errors.New("song not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *LibraryDB) GetLyrics(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]models.Lyrics, error) {
+	const op = "storage.library.GetLyrics"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return nil, err
+	}
+
+	q := `
+		SELECT lang, is_original, text, updated_at
+		FROM lyrics
+		WHERE song_id = $1
+		ORDER BY is_original DESC, lang;
+	`
+	db.log.Debug("get lyrics query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, songID)
+	if err != nil {
+		db.log.Error("failed to get lyrics", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	lyrics := []models.Lyrics{}
+	for rows.Next() {
+		var l models.Lyrics
+		if err := rows.Scan(&l.Lang, &l.Original, &l.Text, &l.UpdatedAt); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		lyrics = append(lyrics, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("lyrics were successfully retrieved", slog.Int("song_id", songID), slog.Int("count", len(lyrics)))
+	return lyrics, nil
+}
+
+// SaveTranslation adds or replaces the translation of the song lyrics, the
+// original lyrics are only changed together with the song text.
+func (db *LibraryDB) SaveTranslation(ctx context.Context, tx pgx.Tx, songID int, lang string, text string, requestID string) error {
+	const op = "storage.library.SaveTranslation"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	q := `
+		INSERT INTO lyrics
+		(song_id, lang, text)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (song_id, lang) DO UPDATE
+		SET text = EXCLUDED.text, updated_at = NOW()
+		WHERE NOT lyrics.is_original
+		RETURNING lang;
+	`
+	db.log.Debug("save translation query", slog.String("query", query.QueryToString(q)))
+
+	var saved string
+	if err := tx.QueryRow(ctx, q, songID, lang, text).Scan(&saved); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("lyrics in this language are the original", slog.Int("song_id", songID), slog.String("lang", lang))
+			return errors.New("lyrics in this language are the original, update the song text instead")
+		}
+		if pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return errors.New("song not found")
+		}
+		db.log.Error("failed to save translation", sl.Err(err))
+		return err
+	}
+
+	if err := db.saveSections(ctx, tx, songID, lang, text); err != nil {
+		return err
+	}
+
+	db.log.Info("translation was successfully saved", slog.Int("song_id", songID), slog.String("lang", lang))
+	return nil
+}
+
+func (db *LibraryDB) DeleteTranslation(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) error {
+	const op = "storage.library.DeleteTranslation"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	q := `
+		DELETE FROM lyrics
+		WHERE song_id = $1 AND lang = $2
+		RETURNING is_original;
+	`
+	db.log.Debug("delete translation query", slog.String("query", query.QueryToString(q)))
+
+	var original bool
+	if err := tx.QueryRow(ctx, q, songID, lang).Scan(&original); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("translation not found", slog.Int("song_id", songID), slog.String("lang", lang))
+			return errors.New("translation not found")
+		}
+		db.log.Error("failed to delete translation", sl.Err(err))
+		return err
+	}
+
+	if original {
+		db.log.Error("original lyrics can not be deleted", slog.Int("song_id", songID))
+		return errors.New("original lyrics can not be deleted")
+	}
+
+	db.log.Info("translation was successfully deleted", slog.Int("song_id", songID), slog.String("lang", lang))
+	return nil
+}
+
+// SetOriginalLang changes the language of the original lyrics, a translation
+// in that language is replaced by the original.
+func (db *LibraryDB) SetOriginalLang(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) error {
+	const op = "storage.library.SetOriginalLang"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	q := `
+		DELETE FROM lyrics
+		WHERE song_id = $1 AND lang = $2 AND NOT is_original;
+	`
+	db.log.Debug("delete replaced translation query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID, lang); err != nil {
+		db.log.Error("failed to delete replaced translation", sl.Err(err))
+		return err
+	}
+
+	q = `
+		UPDATE lyrics
+		SET lang = $2, updated_at = NOW()
+		WHERE song_id = $1 AND is_original;
+	`
+	db.log.Debug("set original lang query", slog.String("query", query.QueryToString(q)))
+
+	tag, err := tx.Exec(ctx, q, songID, lang)
+	if err != nil {
+		db.log.Error("failed to set original lang", sl.Err(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		db.log.Error("song not found", slog.Int("song_id", songID))
+		return errors.New("song not found")
+	}
+
+	q = `
+		UPDATE library
+		SET search_config = lang_search_config($2)
+		WHERE id = $1;
+	`
+	db.log.Debug("set search config query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID, lang); err != nil {
+		db.log.Error("failed to set search config", sl.Err(err))
+		return err
+	}
+
+	db.log.Info("original lang was successfully set", slog.Int("song_id", songID), slog.String("lang", lang))
+	return nil
+}
+
+// syncOriginalLyrics copies the song text to the original lyrics, creating
+// them in an undetermined language for a new song, and parses their sections.
+func (db *LibraryDB) syncOriginalLyrics(ctx context.Context, tx pgx.Tx, songID int) error {
+	q := `
+		WITH updated AS (
+			UPDATE lyrics ly
+			SET text = l.text, updated_at = NOW()
+			FROM library l
+			WHERE l.id = $1 AND ly.song_id = l.id AND ly.is_original
+			RETURNING ly.song_id
+		)
+		INSERT INTO lyrics (song_id, lang, text, is_original)
+		SELECT id, 'und', text, TRUE
+		FROM library
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM updated);
+	`
+	db.log.Debug("sync original lyrics query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID); err != nil {
+		db.log.Error("failed to sync original lyrics", sl.Err(err))
+		return err
+	}
+
+	q = `
+		SELECT lang, text
+		FROM lyrics
+		WHERE song_id = $1 AND is_original;
+	`
+	db.log.Debug("get original lyrics query", slog.String("query", query.QueryToString(q)))
+
+	var lang, text string
+	if err := tx.QueryRow(ctx, q, songID).Scan(&lang, &text); err != nil {
+		db.log.Error("failed to get original lyrics", sl.Err(err))
+		return err
+	}
+
+	return db.saveSections(ctx, tx, songID, lang, text)
+}
@@ -0,0 +1,198 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *LibraryDB) GetPeople(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.Person, error) {
+	const op = "storage.library.GetPeople"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT p.id, p.name,
+			(SELECT COUNT(DISTINCT sp.song_id) FROM song_people sp WHERE sp.person_id = p.id)
+		FROM people p
+		ORDER BY LOWER(p.name), p.id
+		LIMIT $1
+		OFFSET $2;
+	`
+	db.log.Debug("get people query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, limit, offset)
+	if err != nil {
+		db.log.Error("failed to get people", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	people := []models.Person{}
+	for rows.Next() {
+		var person models.Person
+		if err := rows.Scan(&person.ID, &person.Name, &person.SongsCount); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		people = append(people, person)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("people were successfully retrieved", slog.Int("count", len(people)))
+	return people, nil
+}
+
+// GetPersonSongs lists the songs the person is credited on across all
+// groups, each with the roles of the person on it.
+func (db *LibraryDB) GetPersonSongs(ctx context.Context, tx pgx.Tx, personID int, limit int, offset int, requestID string) ([]models.PersonSong, error) {
+	const op = "storage.library.GetPersonSongs"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT EXISTS (SELECT 1 FROM people WHERE id = $1);
+	`
+	db.log.Debug("check person query", slog.String("query", query.QueryToString(q)))
+
+	var exists bool
+	if err := tx.QueryRow(ctx, q, personID).Scan(&exists); err != nil {
+		db.log.Error("failed to check person", sl.Err(err))
+		return nil, err
+	}
+	if !exists {
+		db.log.Error("person not found", slog.Int("person_id", personID))
+		return nil, errors.New("person not found")
+	}
+
+	q = fmt.Sprintf(`
+		SELECT %s,
+			ARRAY(
+				SELECT sp.role FROM song_people sp
+				WHERE sp.song_id = l.id AND sp.person_id = $1
+				ORDER BY sp.role
+			)
+		FROM library l
+		JOIN artists a ON a.id = l.artist_id
+		WHERE l.deleted_at IS NULL
+			AND EXISTS (SELECT 1 FROM song_people sp WHERE sp.song_id = l.id AND sp.person_id = $1)
+		ORDER BY l.release_date, l.id
+		LIMIT $2
+		OFFSET $3;
+	`, songColumns)
+	db.log.Debug("get person songs query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, personID, limit, offset)
+	if err != nil {
+		db.log.Error("failed to get person songs", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	songs := []models.PersonSong{}
+	for rows.Next() {
+		var song models.PersonSong
+		if err := rows.Scan(append(songFields(&song.Song), &song.Roles)...); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		songs = append(songs, song)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("person songs were successfully retrieved", slog.Int("person_id", personID), slog.Int("count", len(songs)))
+	return songs, nil
+}
+
+// AddPersonCredit credits the person on the song with the given role,
+// unknown people are created. Returns the person ID.
+func (db *LibraryDB) AddPersonCredit(ctx context.Context, tx pgx.Tx, songID int, credit dto.PersonCredit, requestID string) (int, error) {
+	const op = "storage.library.AddPersonCredit"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return 0, err
+	}
+
+	q := `
+		INSERT INTO people (name)
+		VALUES ($1)
+		ON CONFLICT (LOWER(name)) DO UPDATE SET name = people.name
+		RETURNING id;
+	`
+	db.log.Debug("get or create person query", slog.String("query", query.QueryToString(q)))
+
+	var personID int
+	if err := tx.QueryRow(ctx, q, credit.Name).Scan(&personID); err != nil {
+		db.log.Error("failed to get or create person", sl.Err(err))
+		return 0, err
+	}
+
+	q = `
+		INSERT INTO song_people
+		(song_id, person_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (song_id, person_id, role) DO NOTHING;
+	`
+	db.log.Debug("add person credit query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID, personID, credit.Role); err != nil {
+		if pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return 0, errors.New("song not found")
+		}
+		db.log.Error("failed to add person credit", sl.Err(err))
+		return 0, err
+	}
+
+	db.log.Info("person credit was successfully added", slog.Int("song_id", songID), slog.Int("person_id", personID), slog.String("role", credit.Role))
+	return personID, nil
+}
+
+func (db *LibraryDB) RemovePersonCredit(ctx context.Context, tx pgx.Tx, songID int, personID int, role string, requestID string) error {
+	const op = "storage.library.RemovePersonCredit"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	q := `
+		DELETE FROM song_people
+		WHERE song_id = $1 AND person_id = $2 AND role = $3;
+	`
+	db.log.Debug("remove person credit query", slog.String("query", query.QueryToString(q)))
+
+	tag, err := tx.Exec(ctx, q, songID, personID, role)
+	if err != nil {
+		db.log.Error("failed to remove person credit", sl.Err(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		db.log.Error("person credit not found", slog.Int("song_id", songID), slog.Int("person_id", personID), slog.String("role", role))
+		return errors.New("person credit not found")
+	}
+
+	db.log.Info("person credit was successfully removed", slog.Int("song_id", songID), slog.Int("person_id", personID), slog.String("role", role))
+	return nil
+}
@@ -0,0 +1,539 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+	"music-library/internal/lib/storage/tools"
+	"music-library/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// visibleEntries numbers the entries of the playlist $1 whose songs are not in
+// the trash, these are the positions clients see. The stored positions keep
+// the trashed entries in place so a restored song goes back where it was.
+const visibleEntries = `
+	SELECT e.id, e.position, ROW_NUMBER() OVER (ORDER BY e.position) AS visible_position
+	FROM playlist_entries e
+	JOIN library l ON l.id = e.song_id
+	WHERE e.playlist_id = $1 AND l.deleted_at IS NULL
+`
+
+func (db *LibraryDB) SavePlaylist(ctx context.Context, tx pgx.Tx, model dto.Playlist, requestID string) (int, error) {
+	const op = "storage.library.SavePlaylist"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		INSERT INTO playlists
+		(name, description, allow_duplicates)
+		VALUES ($1, $2, $3)
+		RETURNING id;
+	`
+	db.log.Debug("save new playlist query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, model.Name, model.Description, model.AllowDuplicates).Scan(&id); err != nil {
+		db.log.Error("failed to save a new playlist", sl.Err(err))
+		return 0, err
+	}
+
+	db.log.Info("new playlist was successfully saved", slog.Int("id", id))
+	return id, nil
+}
+
+func (db *LibraryDB) GetPlaylists(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.Playlist, error) {
+	const op = "storage.library.GetPlaylists"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT p.id, p.name, p.description, p.allow_duplicates,
+			(
+				SELECT COUNT(*) FROM playlist_entries e
+				JOIN library l ON l.id = e.song_id
+				WHERE e.playlist_id = p.id AND l.deleted_at IS NULL
+			),
+			p.created_at, p.updated_at
+		FROM playlists p
+		ORDER BY p.id
+		LIMIT $1
+		OFFSET $2;
+	`
+	db.log.Debug("get playlists query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, limit, offset)
+	if err != nil {
+		db.log.Error("failed to get playlists", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	playlists := []models.Playlist{}
+	for rows.Next() {
+		var p models.Playlist
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.AllowDuplicates, &p.EntriesCount, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		playlists = append(playlists, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("playlists were successfully retrieved", slog.Int("count", len(playlists)))
+	return playlists, nil
+}
+
+func (db *LibraryDB) GetPlaylist(ctx context.Context, tx pgx.Tx, playlistID int, requestID string) (models.Playlist, error) {
+	const op = "storage.library.GetPlaylist"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT p.id, p.name, p.description, p.allow_duplicates,
+			(
+				SELECT COUNT(*) FROM playlist_entries e
+				JOIN library l ON l.id = e.song_id
+				WHERE e.playlist_id = p.id AND l.deleted_at IS NULL
+			),
+			p.created_at, p.updated_at
+		FROM playlists p
+		WHERE p.id = $1;
+	`
+	db.log.Debug("get playlist query", slog.String("query", query.QueryToString(q)))
+
+	var p models.Playlist
+	if err := tx.QueryRow(ctx, q, playlistID).Scan(&p.ID, &p.Name, &p.Description, &p.AllowDuplicates, &p.EntriesCount, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("playlist not found", slog.Int("playlist_id", playlistID))
+			return models.Playlist{}, errors.New("playlist not found")
+		}
+		db.log.Error("failed to get playlist", sl.Err(err))
+		return models.Playlist{}, err
+	}
+
+	q = fmt.Sprintf(`
+		SELECT e.id, ROW_NUMBER() OVER (ORDER BY e.position), e.added_at, %s
+		FROM playlist_entries e
+		JOIN library l ON l.id = e.song_id
+		JOIN artists a ON a.id = l.artist_id
+		WHERE e.playlist_id = $1 AND l.deleted_at IS NULL
+		ORDER BY e.position;
+	`, songColumns)
+	db.log.Debug("get playlist entries query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, playlistID)
+	if err != nil {
+		db.log.Error("failed to get playlist entries", sl.Err(err))
+		return models.Playlist{}, err
+	}
+	defer rows.Close()
+
+	p.Entries = []models.PlaylistEntry{}
+	for rows.Next() {
+		var entry models.PlaylistEntry
+		fields := append([]any{&entry.ID, &entry.Position, &entry.AddedAt}, songFields(&entry.Song)...)
+		if err := rows.Scan(fields...); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return models.Playlist{}, err
+		}
+		p.Entries = append(p.Entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return models.Playlist{}, err
+	}
+
+	db.log.Info("playlist was successfully retrieved", slog.Int("playlist_id", playlistID))
+	return p, nil
+}
+
+func (db *LibraryDB) UpdatePlaylist(ctx context.Context, tx pgx.Tx, updateModel dto.UpdatePlaylist, requestID string) error {
+	const op = "storage.library.UpdatePlaylist"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+	strParams, params := tools.GetPlaylistUpdateParams(updateModel)
+
+	q := fmt.Sprintf(`
+		UPDATE playlists
+		SET %s
+		WHERE id = $%d
+		RETURNING id;
+	`, strParams, len(params)+1)
+
+	params = append(params, updateModel.ID)
+
+	db.log.Debug("update playlist query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, params...).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("playlist not found", slog.Int("playlist_id", updateModel.ID))
+			return errors.New("playlist not found")
+		}
+		db.log.Error("failed to update playlist", sl.Err(err))
+		return err
+	}
+
+	db.log.Info("playlist was successfully updated", slog.Int("id", id))
+	return nil
+}
+
+func (db *LibraryDB) DeletePlaylist(ctx context.Context, tx pgx.Tx, playlistID int, requestID string) error {
+	const op = "storage.library.DeletePlaylist"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		DELETE FROM playlists
+		WHERE id = $1
+		RETURNING id;
+	`
+	db.log.Debug("delete playlist query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, playlistID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("playlist not found", slog.Int("playlist_id", playlistID))
+			return errors.New("playlist not found")
+		}
+		db.log.Error("failed to delete playlist", sl.Err(err))
+		return err
+	}
+
+	db.log.Info("playlist was successfully deleted", slog.Int("id", id))
+	return nil
+}
+
+// AddPlaylistEntry inserts the song at the given position shifting the
+// following entries down. Position 0 or a position past the end appends it.
+// The position counts only the entries of songs outside the trash.
+func (db *LibraryDB) AddPlaylistEntry(ctx context.Context, tx pgx.Tx, playlistID int, entry dto.AddPlaylistEntry, requestID string) (int, int, error) {
+	const op = "storage.library.AddPlaylistEntry"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, entry.SongID); err != nil {
+		return 0, 0, err
+	}
+
+	allowDuplicates, count, err := db.lockPlaylist(ctx, tx, playlistID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !allowDuplicates {
+		q := `
+			SELECT EXISTS (
+				SELECT 1 FROM playlist_entries
+				WHERE playlist_id = $1 AND song_id = $2
+			);
+		`
+		db.log.Debug("check playlist duplicate query", slog.String("query", query.QueryToString(q)))
+
+		var exists bool
+		if err := tx.QueryRow(ctx, q, playlistID, entry.SongID).Scan(&exists); err != nil {
+			db.log.Error("failed to check playlist duplicate", sl.Err(err))
+			return 0, 0, err
+		}
+		if exists {
+			db.log.Error("song is already in the playlist", slog.Int("song_id", entry.SongID))
+			return 0, 0, storage.ErrSongInPlaylist
+		}
+	}
+
+	position := entry.Position
+	if position <= 0 || position > count {
+		position = count + 1
+	}
+
+	stored, err := db.storedPosition(ctx, tx, playlistID, position)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	q := `
+		UPDATE playlist_entries
+		SET position = position + 1
+		WHERE playlist_id = $1 AND position >= $2;
+	`
+	db.log.Debug("shift playlist entries query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, playlistID, stored); err != nil {
+		db.log.Error("failed to shift playlist entries", sl.Err(err))
+		return 0, 0, err
+	}
+
+	q = `
+		INSERT INTO playlist_entries
+		(playlist_id, song_id, position)
+		VALUES ($1, $2, $3)
+		RETURNING id;
+	`
+	db.log.Debug("add playlist entry query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, playlistID, entry.SongID, stored).Scan(&id); err != nil {
+		if pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("song not found", slog.Int("song_id", entry.SongID))
+			return 0, 0, errors.New("song not found")
+		}
+		db.log.Error("failed to add playlist entry", sl.Err(err))
+		return 0, 0, err
+	}
+
+	if err := db.touchPlaylists(ctx, tx, []int{playlistID}); err != nil {
+		return 0, 0, err
+	}
+
+	db.log.Info("playlist entry was successfully added", slog.Int("id", id), slog.Int("position", position))
+	return id, position, nil
+}
+
+func (db *LibraryDB) RemovePlaylistEntry(ctx context.Context, tx pgx.Tx, playlistID int, entryID int, requestID string) error {
+	const op = "storage.library.RemovePlaylistEntry"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if _, _, err := db.lockPlaylist(ctx, tx, playlistID); err != nil {
+		return err
+	}
+
+	q := `
+		DELETE FROM playlist_entries
+		WHERE playlist_id = $1 AND id = $2
+		RETURNING id;
+	`
+	db.log.Debug("remove playlist entry query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, playlistID, entryID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("playlist entry not found", slog.Int("entry_id", entryID))
+			return errors.New("playlist entry not found")
+		}
+		db.log.Error("failed to remove playlist entry", sl.Err(err))
+		return err
+	}
+
+	if err := db.renumberPlaylists(ctx, tx, []int{playlistID}); err != nil {
+		return err
+	}
+
+	if err := db.touchPlaylists(ctx, tx, []int{playlistID}); err != nil {
+		return err
+	}
+
+	db.log.Info("playlist entry was successfully removed", slog.Int("id", id))
+	return nil
+}
+
+// MovePlaylistEntry moves the entry to the given position, a position past
+// the end moves it to the last place. As in AddPlaylistEntry the position
+// counts only the entries of songs outside the trash, the entries of trashed
+// songs can not be moved.
+func (db *LibraryDB) MovePlaylistEntry(ctx context.Context, tx pgx.Tx, playlistID int, entryID int, position int, requestID string) (int, error) {
+	const op = "storage.library.MovePlaylistEntry"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	_, count, err := db.lockPlaylist(ctx, tx, playlistID)
+	if err != nil {
+		return 0, err
+	}
+
+	q := fmt.Sprintf(`
+		SELECT position, visible_position
+		FROM (%s) v
+		WHERE id = $2;
+	`, visibleEntries)
+	db.log.Debug("get playlist entry position query", slog.String("query", query.QueryToString(q)))
+
+	var current, visible int
+	if err := tx.QueryRow(ctx, q, playlistID, entryID).Scan(&current, &visible); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("playlist entry not found", slog.Int("entry_id", entryID))
+			return 0, errors.New("playlist entry not found")
+		}
+		db.log.Error("failed to get playlist entry position", sl.Err(err))
+		return 0, err
+	}
+
+	if position > count {
+		position = count
+	}
+
+	if position != visible {
+		// the entry takes the stored place of the one now at the position,
+		// the entries in between shift with trashed ones keeping their order
+		target, err := db.storedPosition(ctx, tx, playlistID, position)
+		if err != nil {
+			return 0, err
+		}
+
+		q = `
+			UPDATE playlist_entries
+			SET position = CASE
+				WHEN id = $2 THEN $3
+				WHEN $3 < $4 THEN position + 1
+				ELSE position - 1
+			END
+			WHERE playlist_id = $1
+			AND position BETWEEN LEAST($3::int, $4::int) AND GREATEST($3::int, $4::int);
+		`
+		db.log.Debug("move playlist entry query", slog.String("query", query.QueryToString(q)))
+
+		if _, err := tx.Exec(ctx, q, playlistID, entryID, target, current); err != nil {
+			db.log.Error("failed to move playlist entry", sl.Err(err))
+			return 0, err
+		}
+
+		if err := db.touchPlaylists(ctx, tx, []int{playlistID}); err != nil {
+			return 0, err
+		}
+	}
+
+	db.log.Info("playlist entry was successfully moved", slog.Int("id", entryID), slog.Int("position", position))
+	return position, nil
+}
+
+// RemoveSongFromPlaylists drops every entry of the song and closes the gaps
+// it leaves, so the song can be deleted from library.
+func (db *LibraryDB) RemoveSongFromPlaylists(ctx context.Context, tx pgx.Tx, songID int, requestID string) error {
+	const op = "storage.library.RemoveSongFromPlaylists"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		WITH removed AS (
+			DELETE FROM playlist_entries
+			WHERE song_id = $1
+			RETURNING playlist_id
+		)
+		SELECT COALESCE(array_agg(DISTINCT playlist_id), '{}')
+		FROM removed;
+	`
+	db.log.Debug("remove song from playlists query", slog.String("query", query.QueryToString(q)))
+
+	var playlistIDs []int
+	if err := tx.QueryRow(ctx, q, songID).Scan(&playlistIDs); err != nil {
+		db.log.Error("failed to remove song from playlists", sl.Err(err))
+		return err
+	}
+
+	if len(playlistIDs) == 0 {
+		return nil
+	}
+
+	if err := db.renumberPlaylists(ctx, tx, playlistIDs); err != nil {
+		return err
+	}
+
+	if err := db.touchPlaylists(ctx, tx, playlistIDs); err != nil {
+		return err
+	}
+
+	db.log.Info("song was successfully removed from playlists", slog.Int("song_id", songID), slog.Int("playlists", len(playlistIDs)))
+	return nil
+}
+
+// lockPlaylist serializes entry changes of one playlist and returns its
+// duplicates policy and the current number of entries outside the trash.
+func (db *LibraryDB) lockPlaylist(ctx context.Context, tx pgx.Tx, playlistID int) (bool, int, error) {
+	q := `
+		SELECT allow_duplicates,
+			(
+				SELECT COUNT(*) FROM playlist_entries e
+				JOIN library l ON l.id = e.song_id
+				WHERE e.playlist_id = $1 AND l.deleted_at IS NULL
+			)
+		FROM playlists
+		WHERE id = $1
+		FOR UPDATE;
+	`
+	db.log.Debug("lock playlist query", slog.String("query", query.QueryToString(q)))
+
+	var allowDuplicates bool
+	var count int
+	if err := tx.QueryRow(ctx, q, playlistID).Scan(&allowDuplicates, &count); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("playlist not found", slog.Int("playlist_id", playlistID))
+			return false, 0, errors.New("playlist not found")
+		}
+		db.log.Error("failed to lock playlist", sl.Err(err))
+		return false, 0, err
+	}
+
+	return allowDuplicates, count, nil
+}
+
+// storedPosition returns the stored position of the entry at the visible
+// position, a position past the last visible entry is the one after all
+// entries.
+func (db *LibraryDB) storedPosition(ctx context.Context, tx pgx.Tx, playlistID int, position int) (int, error) {
+	q := fmt.Sprintf(`
+		SELECT COALESCE(
+			(SELECT position FROM (%s) v WHERE visible_position = $2),
+			(SELECT COALESCE(MAX(position), 0) + 1 FROM playlist_entries WHERE playlist_id = $1)
+		);
+	`, visibleEntries)
+	db.log.Debug("get stored position query", slog.String("query", query.QueryToString(q)))
+
+	var stored int
+	if err := tx.QueryRow(ctx, q, playlistID, position).Scan(&stored); err != nil {
+		db.log.Error("failed to get stored position", sl.Err(err))
+		return 0, err
+	}
+
+	return stored, nil
+}
+
+func (db *LibraryDB) renumberPlaylists(ctx context.Context, tx pgx.Tx, playlistIDs []int) error {
+	q := `
+		UPDATE playlist_entries e
+		SET position = r.position
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY playlist_id ORDER BY position) AS position
+			FROM playlist_entries
+			WHERE playlist_id = ANY($1)
+		) r
+		WHERE e.id = r.id AND e.position <> r.position;
+	`
+	db.log.Debug("renumber playlists query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, playlistIDs); err != nil {
+		db.log.Error("failed to renumber playlists", sl.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (db *LibraryDB) touchPlaylists(ctx context.Context, tx pgx.Tx, playlistIDs []int) error {
+	q := `
+		UPDATE playlists
+		SET updated_at = NOW()
+		WHERE id = ANY($1);
+	`
+	db.log.Debug("touch playlists query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, playlistIDs); err != nil {
+		db.log.Error("failed to touch playlists", sl.Err(err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"music-library/internal/domain/dto"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// LinkSongs marks the song as a version of the original, linking the same
+// pair again changes the relation type. Links which would make a song a
+// version of itself through other songs are rejected.
+func (db *LibraryDB) LinkSongs(ctx context.Context, tx pgx.Tx, songID int, relation dto.SongRelation, requestID string) error {
+	const op = "storage.library.LinkSongs"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if songID == relation.OriginalID {
+		db.log.Error("song can not be a version of itself", slog.Int("song_id", songID))
+		return errors.New("song can not be a version of itself")
+	}
+
+	for _, id := range []int{songID, relation.OriginalID} {
+		if err := db.checkSong(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	// concurrent links could close a cycle which neither of them sees
+	q := `
+		SELECT pg_advisory_xact_lock(hashtext('song_relations'));
+	`
+	db.log.Debug("lock song relations query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q); err != nil {
+		db.log.Error("failed to lock song relations", sl.Err(err))
+		return err
+	}
+
+	q = `
+		WITH RECURSIVE originals(id) AS (
+			SELECT $2::INTEGER
+			UNION
+			SELECT r.original_id
+			FROM song_relations r
+			JOIN originals o ON r.song_id = o.id
+		)
+		SELECT EXISTS (SELECT 1 FROM originals WHERE id = $1);
+	`
+	db.log.Debug("check relation cycle query", slog.String("query", query.QueryToString(q)))
+
+	var cycle bool
+	if err := tx.QueryRow(ctx, q, songID, relation.OriginalID).Scan(&cycle); err != nil {
+		db.log.Error("failed to check relation cycle", sl.Err(err))
+		return err
+	}
+	if cycle {
+		db.log.Error("relation would create a cycle", slog.Int("song_id", songID), slog.Int("original_id", relation.OriginalID))
+		return errors.New("the original is already a version of the song")
+	}
+
+	q = `
+		INSERT INTO song_relations
+		(song_id, original_id, relation)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (song_id, original_id) DO UPDATE
+		SET relation = EXCLUDED.relation;
+	`
+	db.log.Debug("link songs query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID, relation.OriginalID, relation.Relation); err != nil {
+		if pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("song not found", slog.Int("song_id", songID), slog.Int("original_id", relation.OriginalID))
+			return errors.New("song not found")
+		}
+		db.log.Error("failed to link songs", sl.Err(err))
+		return err
+	}
+
+	db.log.Info("songs were successfully linked", slog.Int("song_id", songID), slog.Int("original_id", relation.OriginalID))
+	return nil
+}
+
+func (db *LibraryDB) UnlinkSongs(ctx context.Context, tx pgx.Tx, songID int, originalID int, requestID string) error {
+	const op = "storage.library.UnlinkSongs"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	q := `
+		DELETE FROM song_relations
+		WHERE song_id = $1 AND original_id = $2;
+	`
+	db.log.Debug("unlink songs query", slog.String("query", query.QueryToString(q)))
+
+	tag, err := tx.Exec(ctx, q, songID, originalID)
+	if err != nil {
+		db.log.Error("failed to unlink songs", sl.Err(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		db.log.Error("song relation not found", slog.Int("song_id", songID), slog.Int("original_id", originalID))
+		return errors.New("song relation not found")
+	}
+
+	db.log.Info("songs were successfully unlinked", slog.Int("song_id", songID), slog.Int("original_id", originalID))
+	return nil
+}
+
+// GetSongVersions walks the relation graph in both directions starting from
+// the song, deleted songs are left out together with their relations.
+func (db *LibraryDB) GetSongVersions(ctx context.Context, tx pgx.Tx, songID int, requestID string) (models.SongVersions, error) {
+	const op = "storage.library.GetSongVersions"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return models.SongVersions{}, err
+	}
+
+	versionsCTE := `
+		WITH RECURSIVE versions(id) AS (
+			SELECT $1::INTEGER
+			UNION
+			SELECT CASE WHEN r.song_id = v.id THEN r.original_id ELSE r.song_id END
+			FROM song_relations r
+			JOIN versions v ON r.song_id = v.id OR r.original_id = v.id
+		)
+	`
+
+	q := fmt.Sprintf(`
+		%s
+		SELECT %s
+		FROM library l
+		JOIN artists a ON a.id = l.artist_id
+		WHERE l.id IN (SELECT id FROM versions) AND l.deleted_at IS NULL
+		ORDER BY l.release_date, l.id;
+	`, versionsCTE, songColumns)
+	db.log.Debug("get song versions query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, songID)
+	if err != nil {
+		db.log.Error("failed to get song versions", sl.Err(err))
+		return models.SongVersions{}, err
+	}
+	defer rows.Close()
+
+	versions := models.SongVersions{Songs: []models.Song{}, Relations: []models.SongRelation{}}
+	for rows.Next() {
+		var song models.Song
+		if err := rows.Scan(songFields(&song)...); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return models.SongVersions{}, err
+		}
+		versions.Songs = append(versions.Songs, song)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return models.SongVersions{}, err
+	}
+
+	q = fmt.Sprintf(`
+		%s
+		SELECT r.song_id, r.original_id, r.relation
+		FROM song_relations r
+		JOIN library s ON s.id = r.song_id AND s.deleted_at IS NULL
+		JOIN library o ON o.id = r.original_id AND o.deleted_at IS NULL
+		WHERE r.song_id IN (SELECT id FROM versions)
+		ORDER BY r.original_id, r.song_id;
+	`, versionsCTE)
+	db.log.Debug("get song relations query", slog.String("query", query.QueryToString(q)))
+
+	rows, err = tx.Query(ctx, q, songID)
+	if err != nil {
+		db.log.Error("failed to get song relations", sl.Err(err))
+		return models.SongVersions{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var relation models.SongRelation
+		if err := rows.Scan(&relation.SongID, &relation.OriginalID, &relation.Relation); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return models.SongVersions{}, err
+		}
+		versions.Relations = append(versions.Relations, relation)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return models.SongVersions{}, err
+	}
+
+	db.log.Info("song versions were successfully retrieved", slog.Int("song_id", songID), slog.Int("count", len(versions.Songs)))
+	return versions, nil
+}
@@ -0,0 +1,192 @@
+package library
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/query"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *LibraryDB) GetSongRevisions(ctx context.Context, tx pgx.Tx, songID int, requestID string) ([]models.SongRevision, error) {
+	const op = "storage.library.GetSongRevisions"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return nil, err
+	}
+
+	q := `
+		SELECT revision, changes, snapshot, request_id, created_at
+		FROM song_revisions
+		WHERE song_id = $1
+		ORDER BY revision DESC;
+	`
+	db.log.Debug("get song revisions query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, songID)
+	if err != nil {
+		db.log.Error("failed to get song revisions", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.SongRevision{}
+	for rows.Next() {
+		var revision models.SongRevision
+		if err := rows.Scan(&revision.Revision, &revision.Changes, &revision.Song, &revision.RequestID, &revision.CreatedAt); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("song revisions were successfully retrieved", slog.Int("song_id", songID), slog.Int("count", len(revisions)))
+	return revisions, nil
+}
+
+func (db *LibraryDB) GetSongRevision(ctx context.Context, tx pgx.Tx, songID int, revision int, requestID string) (models.SongRevision, error) {
+	const op = "storage.library.GetSongRevision"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT revision, changes, snapshot, request_id, created_at
+		FROM song_revisions
+		WHERE song_id = $1 AND revision = $2;
+	`
+	db.log.Debug("get song revision query", slog.String("query", query.QueryToString(q)))
+
+	var rev models.SongRevision
+	if err := tx.QueryRow(ctx, q, songID, revision).Scan(&rev.Revision, &rev.Changes, &rev.Song, &rev.RequestID, &rev.CreatedAt); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("song revision not found", slog.Int("song_id", songID), slog.Int("revision", revision))
+			return models.SongRevision{}, errors.New("song revision not found")
+		}
+		db.log.Error("failed to get song revision", sl.Err(err))
+		return models.SongRevision{}, err
+	}
+
+	db.log.Info("song revision was successfully retrieved", slog.Int("song_id", songID), slog.Int("revision", revision))
+	return rev, nil
+}
+
+// songState reads the current state of the song, with lock the row stays
+// locked until the end of the transaction so revisions are numbered in order
+// and a song in the trash is not found.
+func (db *LibraryDB) songState(ctx context.Context, tx pgx.Tx, songID int, lock bool) (models.SongState, error) {
+	q := `
+		SELECT a.name, l.song, ` + releaseDateColumn + `, l.text,
+			COALESCE((SELECT ln.url FROM song_links ln WHERE ln.song_id = l.id AND ln.is_primary), ''),
+			l.duration, l.bpm, l.musical_key, l.isrc, l.explicit
+		FROM library l
+		JOIN artists a ON a.id = l.artist_id
+		WHERE l.id = $1
+	`
+	if lock {
+		q += " AND l.deleted_at IS NULL FOR UPDATE OF l"
+	}
+	db.log.Debug("get song state query", slog.String("query", query.QueryToString(q)))
+
+	var state models.SongState
+	if err := tx.QueryRow(ctx, q, songID).Scan(
+		&state.Group, &state.Song, &state.ReleaseDate, &state.Text, &state.Patronymic,
+		&state.Duration, &state.BPM, &state.Key, &state.ISRC, &state.Explicit,
+	); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return models.SongState{}, errors.New("song not found")
+		}
+		db.log.Error("failed to get song state", sl.Err(err))
+		return models.SongState{}, err
+	}
+
+	return state, nil
+}
+
+// saveRevision records the current state of the song as its next revision
+// along with the fields changed since prev, a nil prev means the song has
+// just been created. Nothing is recorded when no field has changed.
+func (db *LibraryDB) saveRevision(ctx context.Context, tx pgx.Tx, songID int, prev *models.SongState, requestID string) error {
+	state, err := db.songState(ctx, tx, songID, false)
+	if err != nil {
+		return err
+	}
+
+	changes, err := stateChanges(prev, state)
+	if err != nil {
+		db.log.Error("failed to compare song states", sl.Err(err))
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	q := `
+		INSERT INTO song_revisions
+		(song_id, revision, changes, snapshot, request_id)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, NULLIF($4, '')
+		FROM song_revisions
+		WHERE song_id = $1
+		RETURNING revision;
+	`
+	db.log.Debug("save song revision query", slog.String("query", query.QueryToString(q)))
+
+	var revision int
+	if err := tx.QueryRow(ctx, q, songID, changes, state, requestID).Scan(&revision); err != nil {
+		db.log.Error("failed to save song revision", sl.Err(err))
+		return err
+	}
+
+	db.log.Debug("song revision was saved", slog.Int("song_id", songID), slog.Int("revision", revision))
+	return nil
+}
+
+func stateChanges(prev *models.SongState, state models.SongState) (map[string]models.FieldChange, error) {
+	oldFields := map[string]any{}
+	if prev != nil {
+		var err error
+		if oldFields, err = stateFields(*prev); err != nil {
+			return nil, err
+		}
+	}
+	newFields, err := stateFields(state)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]models.FieldChange)
+	for field, val := range newFields {
+		if !reflect.DeepEqual(oldFields[field], val) {
+			changes[field] = models.FieldChange{Old: oldFields[field], New: val}
+		}
+	}
+	return changes, nil
+}
+
+// stateFields flattens the state by its json names, so the changes are keyed
+// the same way the song is in responses.
+func stateFields(state models.SongState) (map[string]any, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]any)
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
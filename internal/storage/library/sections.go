@@ -0,0 +1,83 @@
+package library
+
+import (
+	"context"
+	"log/slog"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/sections"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetSongSections returns the stored sections of the lyrics in the language,
+// lyrics that were never parsed have none.
+func (db *LibraryDB) GetSongSections(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) ([]sections.Section, error) {
+	const op = "storage.library.GetSongSections"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT position, type, type_index, label, text
+		FROM song_sections
+		WHERE song_id = $1 AND lang = $2
+		ORDER BY position;
+	`
+	db.log.Debug("get song sections query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, songID, lang)
+	if err != nil {
+		db.log.Error("failed to get song sections", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	secs := []sections.Section{}
+	for rows.Next() {
+		var sec sections.Section
+		if err := rows.Scan(&sec.Position, &sec.Type, &sec.Index, &sec.Label, &sec.Text); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		secs = append(secs, sec)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("song sections were successfully retrieved", slog.Int("song_id", songID), slog.String("lang", lang), slog.Int("count", len(secs)))
+	return secs, nil
+}
+
+// saveSections parses the lyrics and replaces their stored sections.
+func (db *LibraryDB) saveSections(ctx context.Context, tx pgx.Tx, songID int, lang string, text string) error {
+	q := `
+		DELETE FROM song_sections
+		WHERE song_id = $1 AND lang = $2;
+	`
+	db.log.Debug("delete song sections query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID, lang); err != nil {
+		db.log.Error("failed to delete song sections", sl.Err(err))
+		return err
+	}
+
+	q = `
+		INSERT INTO song_sections
+		(song_id, lang, position, type, type_index, label, text)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+	db.log.Debug("save song section query", slog.String("query", query.QueryToString(q)))
+
+	for _, sec := range sections.Parse(text) {
+		if _, err := tx.Exec(ctx, q, songID, lang, sec.Position, sec.Type, sec.Index, sec.Label, sec.Text); err != nil {
+			db.log.Error("failed to save song section", slog.Int("position", sec.Position), sl.Err(err))
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/lrc"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveSyncedLyrics replaces the timed lines of the song in the language.
+func (db *LibraryDB) SaveSyncedLyrics(ctx context.Context, tx pgx.Tx, songID int, lang string, lines []lrc.Line, requestID string) error {
+	const op = "storage.library.SaveSyncedLyrics"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	q := `
+		DELETE FROM synced_lyrics
+		WHERE song_id = $1 AND lang = $2;
+	`
+	db.log.Debug("delete synced lyrics query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID, lang); err != nil {
+		db.log.Error("failed to delete synced lyrics", sl.Err(err))
+		return err
+	}
+
+	q = `
+		INSERT INTO synced_lyrics
+		(song_id, lang, position, start_ms, end_ms, text, words)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+	db.log.Debug("save synced line query", slog.String("query", query.QueryToString(q)))
+
+	for i, line := range lines {
+		// lines without word timings store NULL rather than an empty array
+		var words any
+		if len(line.Words) > 0 {
+			words = line.Words
+		}
+		if _, err := tx.Exec(ctx, q, songID, lang, i+1, line.StartMs, line.EndMs, line.Text, words); err != nil {
+			db.log.Error("failed to save synced line", slog.Int("position", i+1), sl.Err(err))
+			return err
+		}
+	}
+
+	db.log.Info("synced lyrics were successfully saved", slog.Int("song_id", songID), slog.String("lang", lang), slog.Int("lines", len(lines)))
+	return nil
+}
+
+func (db *LibraryDB) DeleteSyncedLyrics(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) error {
+	const op = "storage.library.DeleteSyncedLyrics"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	q := `
+		DELETE FROM synced_lyrics
+		WHERE song_id = $1 AND lang = $2;
+	`
+	db.log.Debug("delete synced lyrics query", slog.String("query", query.QueryToString(q)))
+
+	tag, err := tx.Exec(ctx, q, songID, lang)
+	if err != nil {
+		db.log.Error("failed to delete synced lyrics", sl.Err(err))
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		db.log.Error("synced lyrics not found", slog.Int("song_id", songID), slog.String("lang", lang))
+		return errors.New("synced lyrics not found")
+	}
+
+	db.log.Info("synced lyrics were successfully deleted", slog.Int("song_id", songID), slog.String("lang", lang))
+	return nil
+}
+
+// GetSyncedLyrics returns the timed lines in the requested language, picked
+// the same way as the plain text: the exact language, then the same primary
+// language subtag, then the language of the original lyrics. The last line
+// ends with the song when its duration is known.
+func (db *LibraryDB) GetSyncedLyrics(ctx context.Context, tx pgx.Tx, songID int, lang string, requestID string) ([]lrc.Line, string, error) {
+	const op = "storage.library.GetSyncedLyrics"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT l.duration, (
+			SELECT s.lang
+			FROM synced_lyrics s
+			WHERE s.song_id = l.id AND (
+				s.lang = $2
+				OR split_part(s.lang, '-', 1) = split_part($2, '-', 1)
+				OR s.lang = (SELECT ly.lang FROM lyrics ly WHERE ly.song_id = l.id AND ly.is_original)
+			)
+			ORDER BY s.lang = $2 DESC, split_part(s.lang, '-', 1) = split_part($2, '-', 1) DESC, s.lang
+			LIMIT 1
+		)
+		FROM library l
+		WHERE l.id = $1 AND l.deleted_at IS NULL;
+	`
+	db.log.Debug("resolve synced lyrics query", slog.String("query", query.QueryToString(q)))
+
+	var duration *int
+	var syncedLang *string
+	if err := tx.QueryRow(ctx, q, songID, lang).Scan(&duration, &syncedLang); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return nil, "", errors.New("song not found")
+		}
+		db.log.Error("failed to resolve synced lyrics", sl.Err(err))
+		return nil, "", err
+	}
+
+	if syncedLang == nil {
+		db.log.Error("synced lyrics not found", slog.Int("song_id", songID), slog.String("lang", lang))
+		return nil, "", errors.New("synced lyrics not found")
+	}
+
+	q = `
+		SELECT start_ms, end_ms, text, COALESCE(words, '[]'::JSONB)
+		FROM synced_lyrics
+		WHERE song_id = $1 AND lang = $2
+		ORDER BY position;
+	`
+	db.log.Debug("get synced lyrics query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, songID, *syncedLang)
+	if err != nil {
+		db.log.Error("failed to get synced lyrics", sl.Err(err))
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	lines := []lrc.Line{}
+	for rows.Next() {
+		var line lrc.Line
+		if err := rows.Scan(&line.StartMs, &line.EndMs, &line.Text, &line.Words); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, "", err
+		}
+		lines = append(lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, "", err
+	}
+
+	if duration != nil {
+		lrc.SetLastEnd(lines, int64(*duration)*1000)
+	}
+
+	db.log.Info("synced lyrics were successfully retrieved", slog.Int("song_id", songID), slog.String("lang", *syncedLang))
+	return lines, *syncedLang, nil
+}
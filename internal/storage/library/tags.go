@@ -0,0 +1,151 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/pgerrors"
+	"music-library/internal/lib/storage/query"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// label describes a dictionary table of song labels (tags or genres) and the
+// table linking it to songs.
+type label struct {
+	name      string
+	table     string
+	linkTable string
+	column    string
+}
+
+var (
+	tagLabel   = label{name: "tag", table: "tags", linkTable: "song_tags", column: "tag_id"}
+	genreLabel = label{name: "genre", table: "genres", linkTable: "song_genres", column: "genre_id"}
+)
+
+func (db *LibraryDB) AddSongTags(ctx context.Context, tx pgx.Tx, songID int, tags []string, requestID string) error {
+	const op = "storage.library.AddSongTags"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	if err := db.addSongLabels(ctx, tx, tagLabel, songID, tags); err != nil {
+		return err
+	}
+
+	db.log.Info("tags were successfully added", slog.Int("song_id", songID), slog.Int("count", len(tags)))
+	return nil
+}
+
+func (db *LibraryDB) RemoveSongTag(ctx context.Context, tx pgx.Tx, songID int, tag string, requestID string) error {
+	const op = "storage.library.RemoveSongTag"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	if err := db.removeSongLabel(ctx, tx, tagLabel, songID, tag); err != nil {
+		return err
+	}
+
+	db.log.Info("tag was successfully removed", slog.Int("song_id", songID), slog.String("tag", tag))
+	return nil
+}
+
+func (db *LibraryDB) AddSongGenres(ctx context.Context, tx pgx.Tx, songID int, genres []string, requestID string) error {
+	const op = "storage.library.AddSongGenres"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	if err := db.addSongLabels(ctx, tx, genreLabel, songID, genres); err != nil {
+		return err
+	}
+
+	db.log.Info("genres were successfully added", slog.Int("song_id", songID), slog.Int("count", len(genres)))
+	return nil
+}
+
+func (db *LibraryDB) RemoveSongGenre(ctx context.Context, tx pgx.Tx, songID int, genre string, requestID string) error {
+	const op = "storage.library.RemoveSongGenre"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	if err := db.checkSong(ctx, tx, songID); err != nil {
+		return err
+	}
+
+	if err := db.removeSongLabel(ctx, tx, genreLabel, songID, genre); err != nil {
+		return err
+	}
+
+	db.log.Info("genre was successfully removed", slog.Int("song_id", songID), slog.String("genre", genre))
+	return nil
+}
+
+func (db *LibraryDB) addSongLabels(ctx context.Context, tx pgx.Tx, l label, songID int, names []string) error {
+	q := fmt.Sprintf(`
+		INSERT INTO %s (name)
+		SELECT unnest($1::text[])
+		ON CONFLICT (LOWER(name)) DO NOTHING;
+	`, l.table)
+	db.log.Debug("create labels query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, names); err != nil {
+		db.log.Error("failed to create labels", slog.String("label", l.name), sl.Err(err))
+		return err
+	}
+
+	q = fmt.Sprintf(`
+		INSERT INTO %s (song_id, %s)
+		SELECT $1, id FROM %s
+		WHERE LOWER(name) = ANY(SELECT LOWER(unnest($2::text[])))
+		ON CONFLICT DO NOTHING;
+	`, l.linkTable, l.column, l.table)
+	db.log.Debug("link labels query", slog.String("query", query.QueryToString(q)))
+
+	if _, err := tx.Exec(ctx, q, songID, names); err != nil {
+		if pgerrors.IsForeignKeyViolation(err) {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return errors.New("song not found")
+		}
+		db.log.Error("failed to link labels", slog.String("label", l.name), sl.Err(err))
+		return err
+	}
+
+	return nil
+}
+
+func (db *LibraryDB) removeSongLabel(ctx context.Context, tx pgx.Tx, l label, songID int, name string) error {
+	q := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE song_id = $1
+		AND %s = (SELECT id FROM %s WHERE LOWER(name) = LOWER($2))
+		RETURNING song_id;
+	`, l.linkTable, l.column, l.table)
+	db.log.Debug("unlink label query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, songID, name).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("label not found", slog.String("label", l.name), slog.Int("song_id", songID))
+			return fmt.Errorf("song has no such %s", l.name)
+		}
+		db.log.Error("failed to unlink label", slog.String("label", l.name), sl.Err(err))
+		return err
+	}
+
+	return nil
+}
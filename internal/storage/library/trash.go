@@ -0,0 +1,160 @@
+package library
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"music-library/internal/domain/models"
+	"music-library/internal/lib/logger/sl"
+	"music-library/internal/lib/logger/with"
+	"music-library/internal/lib/storage/query"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (db *LibraryDB) GetTrash(ctx context.Context, tx pgx.Tx, limit int, offset int, requestID string) ([]models.TrashItem, error) {
+	const op = "storage.library.GetTrash"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := fmt.Sprintf(`
+		SELECT %s, l.deleted_at
+		FROM library l
+		JOIN artists a ON a.id = l.artist_id
+		WHERE l.deleted_at IS NOT NULL
+		ORDER BY l.deleted_at DESC, l.id
+		LIMIT $1
+		OFFSET $2;
+	`, songColumns)
+	db.log.Debug("get trash query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, limit, offset)
+	if err != nil {
+		db.log.Error("failed to get trash", sl.Err(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.TrashItem{}
+	for rows.Next() {
+		var item models.TrashItem
+		if err := rows.Scan(append(songFields(&item.Song), &item.DeletedAt)...); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("trash was successfully retrieved", slog.Int("count", len(items)))
+	return items, nil
+}
+
+func (db *LibraryDB) RestoreSong(ctx context.Context, tx pgx.Tx, songID int, requestID string) error {
+	const op = "storage.library.RestoreSong"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		UPDATE library
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id, dedup_key;
+	`
+	db.log.Debug("restore song query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	var key string
+	if err := tx.QueryRow(ctx, q, songID).Scan(&id, &key); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("song not found in trash", slog.Int("song_id", songID))
+			return errors.New("song not found in trash")
+		}
+		db.log.Error("failed to restore song", sl.Err(err))
+		return err
+	}
+
+	if err := db.checkDuplicate(ctx, tx, key, id); err != nil {
+		return err
+	}
+
+	db.log.Info("song was successfully restored", slog.Int("id", id))
+	return nil
+}
+
+// PurgeSong deletes the song for good whether it is in the trash or not, the
+// song has to be removed from playlists beforehand.
+func (db *LibraryDB) PurgeSong(ctx context.Context, tx pgx.Tx, songID int, requestID string) error {
+	const op = "storage.library.PurgeSong"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		DELETE FROM library
+		WHERE id = $1
+		RETURNING id;
+	`
+	db.log.Debug("purge song query", slog.String("query", query.QueryToString(q)))
+
+	var id int
+	if err := tx.QueryRow(ctx, q, songID).Scan(&id); err != nil {
+		if err == pgx.ErrNoRows {
+			db.log.Error("song not found", slog.Int("song_id", songID))
+			return errors.New("song not found")
+		}
+		db.log.Error("failed to purge song", sl.Err(err))
+		return err
+	}
+
+	db.log.Info("song was successfully purged", slog.Int("id", id))
+	return nil
+}
+
+// GetExpiredTrash returns the songs deleted before the given time, the rows
+// are locked so a concurrent restore waits for the purge to finish.
+func (db *LibraryDB) GetExpiredTrash(ctx context.Context, tx pgx.Tx, before time.Time, requestID string) ([]int, error) {
+	const op = "storage.library.GetExpiredTrash"
+
+	db.log = with.WithOpAndRequestID(db.log, op, requestID)
+
+	q := `
+		SELECT id
+		FROM library
+		WHERE deleted_at < $1
+		ORDER BY id
+		FOR UPDATE SKIP LOCKED;
+	`
+	db.log.Debug("get expired trash query", slog.String("query", query.QueryToString(q)))
+
+	rows, err := tx.Query(ctx, q, before)
+	if err != nil {
+		db.log.Error("failed to get expired trash", sl.Err(err))
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			db.log.Error("failed to scan row", sl.Err(err))
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		db.log.Error("failed to scan rows", sl.Err(err))
+		return nil, err
+	}
+
+	db.log.Info("expired trash was successfully retrieved", slog.Int("count", len(ids)))
+	return ids, nil
+}
@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSongInPlaylist       = errors.New("song is already in the playlist")
+	ErrUnsupportedMediaType = errors.New("unsupported file type, allowed are JPEG, PNG, GIF, WebP and PDF")
+)
+
+// DuplicateSongError is returned when the group already has a song with the
+// same name, ID is the song in the library.
+type DuplicateSongError struct {
+	ID int
+}
+
+func (e *DuplicateSongError) Error() string {
+	return fmt.Sprintf("song is already in the library with id %d", e.ID)
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_song_relations_original_id;
+DROP TABLE IF EXISTS song_relations;
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS song_relations (
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    original_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    relation TEXT NOT NULL CHECK (relation IN ('cover_of', 'remix_of', 'live_of', 'acoustic_of')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (song_id, original_id),
+    CHECK (song_id <> original_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_relations_original_id ON song_relations(original_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_song_artists_artist_id;
+DROP INDEX IF EXISTS idx_song_artists_primary;
+DROP TABLE IF EXISTS song_artists;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS song_artists (
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    artist_id INTEGER NOT NULL REFERENCES artists(id) ON DELETE RESTRICT,
+    role TEXT NOT NULL CHECK (role IN ('primary', 'featuring', 'producer')),
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (song_id, artist_id, role)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_song_artists_primary ON song_artists(song_id) WHERE role = 'primary';
+CREATE INDEX IF NOT EXISTS idx_song_artists_artist_id ON song_artists(artist_id);
+
+INSERT INTO song_artists (song_id, artist_id, role)
+SELECT id, artist_id, 'primary'
+FROM library
+ON CONFLICT DO NOTHING;
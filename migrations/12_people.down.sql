@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_song_people_person_id;
+DROP TABLE IF EXISTS song_people;
+DROP INDEX IF EXISTS idx_people_name;
+DROP TABLE IF EXISTS people;
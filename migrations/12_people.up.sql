@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS people (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_people_name ON people(LOWER(name));
+
+CREATE TABLE IF NOT EXISTS song_people (
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    person_id INTEGER NOT NULL REFERENCES people(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('composer', 'lyricist', 'arranger')),
+    PRIMARY KEY (song_id, person_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_people_person_id ON song_people(person_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_lyrics_original;
+DROP TABLE IF EXISTS lyrics;
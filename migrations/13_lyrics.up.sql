@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS lyrics (
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    lang TEXT NOT NULL,
+    text TEXT NOT NULL,
+    is_original BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (song_id, lang)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_lyrics_original ON lyrics(song_id) WHERE is_original;
+
+-- the language of the existing lyrics is not known, "und" is the BCP-47 tag
+-- for an undetermined language
+INSERT INTO lyrics (song_id, lang, text, is_original)
+SELECT id, 'und', text, TRUE
+FROM library
+ON CONFLICT DO NOTHING;
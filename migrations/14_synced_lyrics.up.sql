@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS synced_lyrics (
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    lang TEXT NOT NULL,
+    position INTEGER NOT NULL CHECK (position > 0),
+    start_ms BIGINT NOT NULL CHECK (start_ms >= 0),
+    end_ms BIGINT CHECK (end_ms >= start_ms),
+    text TEXT NOT NULL,
+    words JSONB,
+    PRIMARY KEY (song_id, lang, position)
+);
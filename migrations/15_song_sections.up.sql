@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS song_sections (
+    song_id INTEGER NOT NULL,
+    lang TEXT NOT NULL,
+    position INTEGER NOT NULL CHECK (position > 0),
+    type TEXT NOT NULL CHECK (type IN ('intro', 'verse', 'pre-chorus', 'chorus', 'post-chorus', 'bridge', 'interlude', 'outro', 'other')),
+    type_index INTEGER NOT NULL CHECK (type_index > 0),
+    label TEXT NOT NULL,
+    text TEXT NOT NULL,
+    PRIMARY KEY (song_id, lang, position),
+    UNIQUE (song_id, lang, type, type_index),
+    FOREIGN KEY (song_id, lang) REFERENCES lyrics(song_id, lang) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- sections are parsed by the service when the lyrics are saved, lyrics saved
+-- before this migration are parsed on read until they are saved again
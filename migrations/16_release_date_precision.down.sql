@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_library_release_date_end;
+
+ALTER TABLE library DROP COLUMN IF EXISTS release_date_end;
+ALTER TABLE library DROP COLUMN IF EXISTS release_date_precision;
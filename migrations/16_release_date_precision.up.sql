@@ -0,0 +1,15 @@
+ALTER TABLE library
+    ADD COLUMN IF NOT EXISTS release_date_precision TEXT NOT NULL DEFAULT 'day'
+        CHECK (release_date_precision IN ('year', 'month', 'day'));
+
+-- release_date is the first day of the period, release_date_end the last one
+ALTER TABLE library
+    ADD COLUMN IF NOT EXISTS release_date_end DATE GENERATED ALWAYS AS ((
+        release_date + CASE release_date_precision
+            WHEN 'year' THEN INTERVAL '1 year'
+            WHEN 'month' THEN INTERVAL '1 month'
+            ELSE INTERVAL '1 day'
+        END - INTERVAL '1 day'
+    )::DATE) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_library_release_date_end ON library(release_date_end);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_library_dedup_key;
+
+ALTER TABLE library DROP COLUMN IF EXISTS dedup_key;
+
+DROP FUNCTION IF EXISTS normalize_key(TEXT);
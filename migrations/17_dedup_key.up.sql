@@ -0,0 +1,21 @@
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent is only STABLE because its dictionary can change, with the
+-- dictionary given explicitly the wrapper can be used in an index
+CREATE OR REPLACE FUNCTION normalize_key(value TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT AS $$
+    SELECT BTRIM(regexp_replace(LOWER(public.unaccent('public.unaccent'::regdictionary, value)), '\s+', ' ', 'g'))
+$$;
+
+ALTER TABLE library ADD COLUMN IF NOT EXISTS dedup_key TEXT;
+
+UPDATE library l
+SET dedup_key = normalize_key(a.name) || E'\n' || normalize_key(l.song)
+FROM artists a
+WHERE a.id = l.artist_id;
+
+ALTER TABLE library ALTER COLUMN dedup_key SET NOT NULL;
+
+-- not unique, the songs saved twice before the key existed are reported by
+-- GET /duplicates and merged by hand
+CREATE INDEX IF NOT EXISTS idx_library_dedup_key ON library(dedup_key) WHERE deleted_at IS NULL;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS attachments (
+    id SERIAL PRIMARY KEY,
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('artwork', 'sheet_music', 'other')),
+    filename TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    sha256 TEXT NOT NULL,
+    blob_key TEXT NOT NULL UNIQUE,
+    thumbnail_key TEXT UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_song_id ON attachments(song_id);
@@ -0,0 +1,8 @@
+CREATE INDEX IF NOT EXISTS idx_library_text ON library(text);
+
+DROP INDEX IF EXISTS idx_library_search_vector;
+
+ALTER TABLE library DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE library DROP COLUMN IF EXISTS search_config;
+
+DROP FUNCTION IF EXISTS lang_search_config(TEXT);
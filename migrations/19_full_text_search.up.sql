@@ -0,0 +1,52 @@
+-- maps the BCP-47 tag of the original lyrics to a text search configuration,
+-- languages without one are only split into words
+CREATE OR REPLACE FUNCTION lang_search_config(lang TEXT) RETURNS regconfig
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT AS $$
+    SELECT (CASE split_part(LOWER(lang), '-', 1)
+        WHEN 'ar' THEN 'arabic'
+        WHEN 'da' THEN 'danish'
+        WHEN 'de' THEN 'german'
+        WHEN 'el' THEN 'greek'
+        WHEN 'en' THEN 'english'
+        WHEN 'es' THEN 'spanish'
+        WHEN 'fi' THEN 'finnish'
+        WHEN 'fr' THEN 'french'
+        WHEN 'ga' THEN 'irish'
+        WHEN 'hu' THEN 'hungarian'
+        WHEN 'id' THEN 'indonesian'
+        WHEN 'it' THEN 'italian'
+        WHEN 'lt' THEN 'lithuanian'
+        WHEN 'nb' THEN 'norwegian'
+        WHEN 'ne' THEN 'nepali'
+        WHEN 'nl' THEN 'dutch'
+        WHEN 'nn' THEN 'norwegian'
+        WHEN 'no' THEN 'norwegian'
+        WHEN 'pt' THEN 'portuguese'
+        WHEN 'ro' THEN 'romanian'
+        WHEN 'ru' THEN 'russian'
+        WHEN 'sv' THEN 'swedish'
+        WHEN 'ta' THEN 'tamil'
+        WHEN 'tr' THEN 'turkish'
+        ELSE 'simple'
+    END)::regconfig
+$$;
+
+ALTER TABLE library ADD COLUMN IF NOT EXISTS search_config regconfig NOT NULL DEFAULT 'simple';
+
+UPDATE library l
+SET search_config = lang_search_config(ly.lang)
+FROM lyrics ly
+WHERE ly.song_id = l.id AND ly.is_original;
+
+-- the title weighs more than the lyrics in the rank
+ALTER TABLE library
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector(search_config, song), 'A') ||
+        setweight(to_tsvector(search_config, text), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_library_search_vector ON library USING GIN (search_vector);
+
+-- a btree does not help LIKE '%...%' and fails on lyrics longer than a
+-- btree page allows
+DROP INDEX IF EXISTS idx_library_text;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_library_song_trgm;
+DROP INDEX IF EXISTS idx_artists_name_trgm;
@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- serve both the fuzzy filters and the LIKE '%...%' ones on the lowercased names
+CREATE INDEX IF NOT EXISTS idx_artists_name_trgm ON artists USING GIN (LOWER(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_library_song_trgm ON library USING GIN (LOWER(song) gin_trgm_ops);
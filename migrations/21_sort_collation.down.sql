@@ -0,0 +1 @@
+DROP COLLATION IF EXISTS natural_sort;
@@ -0,0 +1,3 @@
+-- sorts names by the Unicode collation algorithm, so Cyrillic and accented
+-- letters come in alphabet order, and numbers in names by their value
+CREATE COLLATION IF NOT EXISTS natural_sort (provider = icu, locale = 'und-u-kn-true');
@@ -0,0 +1,3 @@
+ALTER TABLE albums
+    ADD COLUMN IF NOT EXISTS release_date_precision TEXT NOT NULL DEFAULT 'day'
+        CHECK (release_date_precision IN ('year', 'month', 'day'));
@@ -0,0 +1,2 @@
+UPDATE albums SET album_type = 'LP' WHERE album_type IS NULL;
+ALTER TABLE albums ALTER COLUMN album_type SET NOT NULL;
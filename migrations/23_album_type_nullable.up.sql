@@ -0,0 +1,2 @@
+-- albums created by an import do not know their type
+ALTER TABLE albums ALTER COLUMN album_type DROP NOT NULL;
@@ -0,0 +1,15 @@
+ALTER TABLE library ADD COLUMN IF NOT EXISTS group_name TEXT;
+
+UPDATE library l
+SET group_name = a.name
+FROM artists a
+WHERE a.id = l.artist_id;
+
+ALTER TABLE library ALTER COLUMN group_name SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_library_group_name ON library(group_name);
+
+DROP INDEX IF EXISTS idx_library_artist_id;
+ALTER TABLE library DROP COLUMN IF EXISTS artist_id;
+
+DROP INDEX IF EXISTS idx_artists_name;
+DROP TABLE IF EXISTS artists;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS artists (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    sort_name TEXT,
+    country TEXT,
+    formed_year INTEGER
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artists_name ON artists(LOWER(name));
+
+INSERT INTO artists (name)
+SELECT DISTINCT ON (LOWER(REGEXP_REPLACE(BTRIM(group_name), '\s+', ' ', 'g')))
+    REGEXP_REPLACE(BTRIM(group_name), '\s+', ' ', 'g')
+FROM library
+ORDER BY LOWER(REGEXP_REPLACE(BTRIM(group_name), '\s+', ' ', 'g')), id
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE library ADD COLUMN IF NOT EXISTS artist_id INTEGER REFERENCES artists(id) ON DELETE RESTRICT;
+
+UPDATE library l
+SET artist_id = a.id
+FROM artists a
+WHERE LOWER(a.name) = LOWER(REGEXP_REPLACE(BTRIM(l.group_name), '\s+', ' ', 'g'));
+
+ALTER TABLE library ALTER COLUMN artist_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_library_group_name;
+ALTER TABLE library DROP COLUMN IF EXISTS group_name;
+
+CREATE INDEX IF NOT EXISTS idx_library_artist_id ON library(artist_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_album_tracks_song_id;
+DROP TABLE IF EXISTS album_tracks;
+
+DROP INDEX IF EXISTS idx_albums_artist_title;
+DROP TABLE IF EXISTS albums;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS albums (
+    id SERIAL PRIMARY KEY,
+    title TEXT NOT NULL,
+    artist_id INTEGER NOT NULL REFERENCES artists(id) ON DELETE RESTRICT,
+    release_date DATE NOT NULL,
+    album_type TEXT NOT NULL CHECK (album_type IN ('LP', 'EP', 'single'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_albums_artist_title ON albums(artist_id, LOWER(title));
+
+CREATE TABLE IF NOT EXISTS album_tracks (
+    album_id INTEGER NOT NULL REFERENCES albums(id) ON DELETE CASCADE,
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL CHECK (position > 0),
+    PRIMARY KEY (album_id, song_id),
+    CONSTRAINT album_tracks_position_key UNIQUE (album_id, position) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+CREATE INDEX IF NOT EXISTS idx_album_tracks_song_id ON album_tracks(song_id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_song_genres_genre_id;
+DROP TABLE IF EXISTS song_genres;
+DROP INDEX IF EXISTS idx_genres_name;
+DROP TABLE IF EXISTS genres;
+
+DROP INDEX IF EXISTS idx_song_tags_tag_id;
+DROP TABLE IF EXISTS song_tags;
+DROP INDEX IF EXISTS idx_tags_name;
+DROP TABLE IF EXISTS tags;
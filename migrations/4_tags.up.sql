@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name ON tags(LOWER(name));
+
+CREATE TABLE IF NOT EXISTS song_tags (
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_tags_tag_id ON song_tags(tag_id);
+
+CREATE TABLE IF NOT EXISTS genres (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_genres_name ON genres(LOWER(name));
+
+CREATE TABLE IF NOT EXISTS song_genres (
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    genre_id INTEGER NOT NULL REFERENCES genres(id) ON DELETE CASCADE,
+    PRIMARY KEY (song_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_song_genres_genre_id ON song_genres(genre_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_playlist_entries_song_id;
+DROP TABLE IF EXISTS playlist_entries;
+DROP TABLE IF EXISTS playlists;
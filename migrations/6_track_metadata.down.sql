@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_library_isrc;
+DROP INDEX IF EXISTS idx_library_musical_key;
+DROP INDEX IF EXISTS idx_library_bpm;
+DROP INDEX IF EXISTS idx_library_duration;
+
+ALTER TABLE library
+    DROP COLUMN IF EXISTS explicit,
+    DROP COLUMN IF EXISTS isrc,
+    DROP COLUMN IF EXISTS musical_key,
+    DROP COLUMN IF EXISTS bpm,
+    DROP COLUMN IF EXISTS duration;
@@ -0,0 +1,11 @@
+ALTER TABLE library
+    ADD COLUMN IF NOT EXISTS duration INTEGER CHECK (duration > 0),
+    ADD COLUMN IF NOT EXISTS bpm NUMERIC(5, 2) CHECK (bpm > 0),
+    ADD COLUMN IF NOT EXISTS musical_key TEXT,
+    ADD COLUMN IF NOT EXISTS isrc TEXT CHECK (isrc ~ '^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$'),
+    ADD COLUMN IF NOT EXISTS explicit BOOLEAN;
+
+CREATE INDEX IF NOT EXISTS idx_library_duration ON library(duration);
+CREATE INDEX IF NOT EXISTS idx_library_bpm ON library(bpm);
+CREATE INDEX IF NOT EXISTS idx_library_musical_key ON library(musical_key);
+CREATE INDEX IF NOT EXISTS idx_library_isrc ON library(isrc);
@@ -0,0 +1,12 @@
+ALTER TABLE library ADD COLUMN IF NOT EXISTS patronymic TEXT NOT NULL DEFAULT '';
+
+UPDATE library l
+SET patronymic = sl.url
+FROM song_links sl
+WHERE sl.song_id = l.id AND sl.is_primary;
+
+ALTER TABLE library ALTER COLUMN patronymic DROP DEFAULT;
+
+DROP INDEX IF EXISTS idx_song_links_primary;
+DROP INDEX IF EXISTS idx_song_links_song_url;
+DROP TABLE IF EXISTS song_links;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS song_links (
+    id SERIAL PRIMARY KEY,
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    url TEXT NOT NULL,
+    label TEXT,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_song_links_song_url ON song_links(song_id, url);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_song_links_primary ON song_links(song_id) WHERE is_primary;
+
+INSERT INTO song_links (song_id, provider, url, is_primary)
+SELECT id,
+    CASE
+        WHEN host ~ '(^|\.)(youtube\.com|youtu\.be)$' THEN 'youtube'
+        WHEN host ~ '(^|\.)bandcamp\.com$' THEN 'bandcamp'
+        WHEN host ~ '(^|\.)(spotify\.com|spotify\.link)$' THEN 'spotify'
+        WHEN host ~ '(^|\.)music\.apple\.com$' THEN 'apple_music'
+        WHEN host ~ '(^|\.)soundcloud\.com$' THEN 'soundcloud'
+        WHEN host ~ '(^|\.)(genius\.com|musixmatch\.com|azlyrics\.com|lyrics\.com)$' THEN 'lyrics'
+        ELSE 'other'
+    END,
+    url,
+    TRUE
+FROM (
+    SELECT id, BTRIM(patronymic) AS url,
+        LOWER(SUBSTRING(BTRIM(patronymic) FROM '^[A-Za-z][A-Za-z0-9+.-]*://(?:[^@/]*@)?([^/:?#]+)')) AS host
+    FROM library
+    WHERE BTRIM(patronymic) <> ''
+) links;
+
+ALTER TABLE library DROP COLUMN IF EXISTS patronymic;
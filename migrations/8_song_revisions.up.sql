@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS song_revisions (
+    id SERIAL PRIMARY KEY,
+    song_id INTEGER NOT NULL REFERENCES library(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    changes JSONB NOT NULL,
+    snapshot JSONB NOT NULL,
+    request_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (song_id, revision)
+);
+
+-- the current state of the existing songs becomes their first revision
+WITH snapshots AS (
+    SELECT l.id AS song_id,
+        jsonb_build_object(
+            'group', a.name,
+            'song', l.song,
+            'releaseDate', to_char(l.release_date, 'DD.MM.YYYY'),
+            'text', l.text,
+            'patronymic', COALESCE((SELECT sl.url FROM song_links sl WHERE sl.song_id = l.id AND sl.is_primary), ''),
+            'duration', l.duration,
+            'bpm', l.bpm,
+            'key', l.musical_key,
+            'isrc', l.isrc,
+            'explicit', l.explicit
+        ) AS snapshot
+    FROM library l
+    JOIN artists a ON a.id = l.artist_id
+)
+INSERT INTO song_revisions (song_id, revision, changes, snapshot)
+SELECT song_id, 1,
+    COALESCE((
+        SELECT jsonb_object_agg(key, jsonb_build_object('old', NULL, 'new', value))
+        FROM jsonb_each(snapshot)
+        WHERE value <> 'null'::jsonb
+    ), '{}'::jsonb),
+    snapshot
+FROM snapshots;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_library_deleted_at;
+
+ALTER TABLE library DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE library ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_library_deleted_at ON library(deleted_at) WHERE deleted_at IS NOT NULL;